@@ -22,6 +22,8 @@ type ApiCommand struct {
 	ServerFlagUrl     string            `short:"s" long:"server" description:"URI of API server to target" env:"CREDHUB_SERVER"`
 	CaCerts           []string          `long:"ca-cert" description:"Trusted CA for API and UAA TLS connections. Multiple flags may be provided." env:"CREDHUB_CA_CERT"`
 	SkipTlsValidation bool              `long:"skip-tls-validation" description:"Skip certificate validation of the API endpoint. Not recommended!"`
+	ClientCert        string            `long:"client-cert" description:"Client certificate for mutual TLS authentication, reloaded when the file changes" env:"CREDHUB_CLIENT_CERT"`
+	ClientKey         string            `long:"client-key" description:"Private key of the client certificate" env:"CREDHUB_CLIENT_KEY"`
 	ConfigCommand
 }
 
@@ -30,7 +32,7 @@ type ApiPositionalArgs struct {
 }
 
 func (c *ApiCommand) Execute([]string) error {
-	var newConfig config.Config
+	newConfig := config.Config{Target: c.config.Target}
 	if c.Server.ServerUrl != "" {
 		newConfig.ApiURL = c.Server.ServerUrl
 	} else if c.ServerFlagUrl != "" {
@@ -60,9 +62,21 @@ func (c *ApiCommand) Execute([]string) error {
 	if newConfig.AuthURL != c.config.AuthURL {
 		RevokeTokenIfNecessary(c.config)
 		MarkTokensAsRevokedInConfig(&c.config)
+	} else {
+		newConfig.AuthProvider = c.config.AuthProvider
 	}
 	newConfig.AccessToken = c.config.AccessToken
 	newConfig.RefreshToken = c.config.RefreshToken
+	newConfig.ClientCertPath = c.config.ClientCertPath
+	newConfig.ClientKeyPath = c.config.ClientKeyPath
+	newConfig.ExecCommand = c.config.ExecCommand
+
+	if c.ClientCert != "" || c.ClientKey != "" {
+		newConfig.ClientCertPath, newConfig.ClientKeyPath, err = clientCertificatePaths(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return err
+		}
+	}
 
 	err = verifyAuthServerConnection(newConfig, newConfig.InsecureSkipVerify)
 	if err != nil {
@@ -80,7 +94,7 @@ func (c *ApiCommand) Execute([]string) error {
 }
 
 func GetApiInfo(serverUrl string, caCerts []string, skipTlsValidation bool, httpTimeout *time.Duration) (*server.Info, error) {
-	credhubClient, err := credhub.New(serverUrl, credhub.CaCerts(caCerts...), credhub.SkipTLSValidation(skipTlsValidation), credhub.SetHttpTimeout(httpTimeout))
+	credhubClient, err := credhub.New(serverUrl, credhub.CaCerts(caCerts...), credhub.SkipTLSValidation(skipTlsValidation), credhub.SetHttpTimeout(httpTimeout), credhub.Logger(util.DebugLogger()))
 	if err != nil {
 		return nil, err
 	}
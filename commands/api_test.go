@@ -90,6 +90,7 @@ var _ = Describe("API", func() {
 			cfg := config.ReadConfig()
 			cfg.AccessToken = "fake_token"
 			cfg.RefreshToken = "fake_refresh"
+			cfg.AuthProvider = config.AuthProviderOIDC
 			config.WriteConfig(cfg)
 
 			session := runCommand("api", apiServer.URL(), "--skip-tls-validation")
@@ -98,6 +99,7 @@ var _ = Describe("API", func() {
 			newCfg := config.ReadConfig()
 			Expect(newCfg.AccessToken).To(Equal("fake_token"))
 			Expect(newCfg.RefreshToken).To(Equal("fake_refresh"))
+			Expect(newCfg.AuthProvider).To(Equal(config.AuthProviderOIDC))
 			Expect(authServer.ReceivedRequests()).Should(HaveLen(0))
 		})
 
@@ -0,0 +1,99 @@
+package commands_test
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/credhub-cli/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Bearer token authentication", func() {
+	BeforeEach(func() {
+		server.RouteToHandler("GET", "/info",
+			RespondWith(http.StatusOK, `{
+				"app":{"name":"CredHub","version":"2.9.0"},
+				"auth-server":{"url":"`+authServer.URL()+`"}
+				}`),
+		)
+		authServer.RouteToHandler("GET", "/info", RespondWith(http.StatusOK, ""))
+	})
+
+	AfterEach(func() {
+		config.RemoveConfig()
+	})
+
+	Describe("credhub login --token", func() {
+		It("saves the token and never refreshes it", func() {
+			server.RouteToHandler("GET", "/api/v1/data",
+				CombineHandlers(
+					VerifyHeaderKV("Authorization", "Bearer some-token"),
+					RespondWith(http.StatusUnauthorized, `{"error":"access_token_expired","error_description":"Access token expired"}`),
+				),
+			)
+
+			session := runCommand("login", "--token", "some-token")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say("Login Successful"))
+
+			cfg := config.ReadConfig()
+			Expect(cfg.AccessToken).To(Equal("some-token"))
+			Expect(cfg.StaticToken).To(BeTrue())
+
+			session = runCommand("find")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("the bearer token was rejected by the server: access_token_expired: Access token expired"))
+			Expect(authServer.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("does not revoke the token on logout", func() {
+			session := runCommand("login", "--token", "some-token")
+			Eventually(session).Should(Exit(0))
+
+			session = runCommand("logout")
+
+			Eventually(session).Should(Exit(0))
+			Expect(authServer.ReceivedRequests()).To(BeEmpty())
+			cfg := config.ReadConfig()
+			Expect(cfg.AccessToken).To(Equal("revoked"))
+			Expect(cfg.StaticToken).To(BeFalse())
+		})
+
+		It("may not be combined with other credentials", func() {
+			session := runCommand("login", "--token", "some-token", "-u", "some-user")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("Client, password, SSO and/or SSO passcode credentials may not be combined."))
+		})
+	})
+
+	Describe("CREDHUB_TOKEN", func() {
+		It("authenticates requests without logging in", func() {
+			cfg := config.ReadConfig()
+			cfg.AccessToken = "revoked"
+			config.WriteConfig(cfg)
+
+			server.RouteToHandler("GET", "/api/v1/data",
+				CombineHandlers(
+					VerifyHeaderKV("Authorization", "Bearer env-token"),
+					RespondWith(http.StatusOK, `{"credentials": []}`),
+				),
+			)
+
+			session := runCommandWithEnv([]string{"CREDHUB_TOKEN=env-token"}, "find")
+
+			Eventually(session).Should(Exit(0))
+			Expect(config.ReadConfig().AccessToken).To(Equal("revoked"))
+
+			session = runCommandWithEnv([]string{"CREDHUB_TOKEN=env-token"}, "--token")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say("Bearer env-token"))
+		})
+	})
+})
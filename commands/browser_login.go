@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/config"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+	"code.cloudfoundry.org/credhub-cli/errors"
+)
+
+// browserLoginTimeout bounds the wait for the user to complete the login in the browser
+const browserLoginTimeout = 5 * time.Minute
+
+type authorizationResult struct {
+	code string
+	err  error
+}
+
+// browserLogin requests tokens with the authorization code grant and PKCE. The user logs in through
+// a browser, which is redirected to a listener on the loopback interface with the authorization code.
+func browserLogin(oauthClient auth.OAuthClient) (string, string, error) {
+	client, ok := oauthClient.(auth.AuthorizationCodeClient)
+	if !ok {
+		return "", "", errors.NewBrowserLoginNotSupportedError()
+	}
+
+	verifier, err := randomURLSafeString()
+	if err != nil {
+		return "", "", err
+	}
+	state, err := randomURLSafeString()
+	if err != nil {
+		return "", "", err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", "", err
+	}
+
+	redirectURI := fmt.Sprintf("http://%s/callback", listener.Addr())
+
+	authorizationURL, err := client.AuthorizationURL(config.AuthClient, redirectURI, state, codeChallenge(verifier))
+	if err != nil {
+		listener.Close()
+		return "", "", err
+	}
+
+	results := make(chan authorizationResult, 1)
+	server := &http.Server{
+		Handler:           authorizationCallbackHandler(state, results),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go server.Serve(listener)
+	defer server.Close()
+
+	// the browser may start without showing anything, e.g. over SSH, so the URL is always printed
+	fmt.Printf("Open the following URL in a browser to log in:\n\n  %s\n\n", authorizationURL)
+	if err := openBrowser(authorizationURL); err == nil {
+		fmt.Println("Your browser has been opened to log in.")
+	}
+	fmt.Println("Waiting for the login to complete...")
+
+	var result authorizationResult
+	select {
+	case result = <-results:
+	case <-time.After(browserLoginTimeout):
+		return "", "", errors.NewBrowserLoginTimeoutError()
+	}
+
+	if result.err != nil {
+		return "", "", result.err
+	}
+
+	return client.AuthorizationCodeGrant(config.AuthClient, config.AuthPassword, result.code, redirectURI, verifier)
+}
+
+// authorizationCallbackHandler reports the first authorization response whose state matches on results
+func authorizationCallbackHandler(state string, results chan<- authorizationResult) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		// ignore requests which were not started by this login, see RFC 6749 section 10.12
+		if query.Get("state") != state {
+			http.Error(w, "The login request is invalid or has expired.", http.StatusBadRequest)
+			return
+		}
+
+		var result authorizationResult
+		switch {
+		case query.Get("error") != "":
+			result.err = errors.NewBrowserLoginDeniedError(query.Get("error"), query.Get("error_description"))
+			http.Error(w, "Login failed. Return to the terminal for details.", http.StatusForbidden)
+		case query.Get("code") == "":
+			result.err = errors.NewBrowserLoginDeniedError("invalid_request", "the authorization response does not include a code")
+			http.Error(w, "Login failed. Return to the terminal for details.", http.StatusBadRequest)
+		default:
+			result.code = query.Get("code")
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.Write([]byte("Login Successful. You may close this window and return to the terminal.\n"))
+		}
+
+		select {
+		case results <- result:
+		default:
+		}
+	})
+	return mux
+}
+
+// openBrowser opens url with the command in the BROWSER environment variable, or the platform's default browser
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch browser := os.Getenv("BROWSER"); {
+	case browser != "":
+		cmd = exec.Command(browser, url)
+	case runtime.GOOS == "darwin":
+		cmd = exec.Command("open", url)
+	case runtime.GOOS == "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+
+	return nil
+}
+
+// randomURLSafeString returns 32 random bytes encoded as a 43 character string, as recommended for PKCE verifiers by RFC 7636
+func randomURLSafeString() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// codeChallenge returns the S256 code challenge of verifier
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,138 @@
+package commands_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+
+	"code.cloudfoundry.org/credhub-cli/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Browser login", func() {
+	var authorizationURLPattern = regexp.MustCompile(`https://\S+/oauth/authorize\?\S+`)
+
+	BeforeEach(func() {
+		server.RouteToHandler("GET", "/info",
+			RespondWith(http.StatusOK, `{
+				"app":{"name":"CredHub","version":"2.9.0"},
+				"auth-server":{"url":"`+authServer.URL()+`"}
+				}`),
+		)
+		authServer.RouteToHandler("GET", "/info", RespondWith(http.StatusOK, ""))
+	})
+
+	AfterEach(func() {
+		config.RemoveConfig()
+	})
+
+	// startBrowserLoginWith runs login --browser with browser as $BROWSER, and returns the authorization URL it prints
+	startBrowserLoginWith := func(browser string) (*Session, *url.URL) {
+		cmd := exec.Command(commandPath, "login", "--browser")
+		cmd.Env = append(os.Environ(), "BROWSER="+browser)
+		session, err := Start(cmd, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(session.Out).Should(Say("Open the following URL in a browser to log in:"))
+		Eventually(session.Out).Should(Say("Waiting for the login to complete..."))
+
+		authorizationURL, err := url.Parse(authorizationURLPattern.FindString(string(session.Out.Contents())))
+		Expect(err).NotTo(HaveOccurred())
+
+		return session, authorizationURL
+	}
+
+	// startBrowserLogin runs login --browser without a browser, and returns the authorization URL it prints
+	startBrowserLogin := func() (*Session, *url.URL) {
+		return startBrowserLoginWith("/non-existent-browser")
+	}
+
+	It("exchanges the authorization code for tokens", func() {
+		var codeVerifier string
+		authServer.RouteToHandler("POST", "/oauth/token",
+			CombineHandlers(
+				VerifyFormKV("grant_type", "authorization_code"),
+				VerifyFormKV("client_id", config.AuthClient),
+				VerifyFormKV("code", "some-code"),
+				func(w http.ResponseWriter, r *http.Request) {
+					codeVerifier = r.PostForm.Get("code_verifier")
+				},
+				RespondWith(http.StatusOK, `{
+					"access_token":"2YotnFZFEjr1zCsicMWpAA",
+					"refresh_token":"erousflkajqwer",
+					"token_type":"bearer",
+					"expires_in":3600}`),
+			),
+		)
+
+		session, authorizationURL := startBrowserLogin()
+		query := authorizationURL.Query()
+		Expect(authorizationURL.Host).To(Equal(authServer.Addr()))
+		Expect(query.Get("client_id")).To(Equal(config.AuthClient))
+		Expect(query.Get("code_challenge_method")).To(Equal("S256"))
+		Expect(query.Get("redirect_uri")).To(MatchRegexp(`^http://127\.0\.0\.1:\d+/callback$`))
+
+		resp, err := http.Get(query.Get("redirect_uri") + "?code=some-code&state=" + url.QueryEscape(query.Get("state")))
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say("Login Successful"))
+
+		challenge := sha256.Sum256([]byte(codeVerifier))
+		Expect(base64.RawURLEncoding.EncodeToString(challenge[:])).To(Equal(query.Get("code_challenge")))
+
+		cfg := config.ReadConfig()
+		Expect(cfg.AccessToken).To(Equal("2YotnFZFEjr1zCsicMWpAA"))
+		Expect(cfg.RefreshToken).To(Equal("erousflkajqwer"))
+	})
+
+	It("ignores redirects with another state", func() {
+		session, authorizationURL := startBrowserLogin()
+		redirectURI := authorizationURL.Query().Get("redirect_uri")
+
+		resp, err := http.Get(redirectURI + "?code=some-code&state=forged")
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		Consistently(session).ShouldNot(Exit())
+
+		resp, err = http.Get(redirectURI + "?error=access_denied&state=" + url.QueryEscape(authorizationURL.Query().Get("state")))
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+
+		Eventually(session).Should(Exit(1))
+		Expect(session.Err).To(Say("The browser login failed: access_denied"))
+	})
+
+	It("prints the authorization URL when the browser opens without showing it", func() {
+		browser, err := exec.LookPath("true")
+		Expect(err).NotTo(HaveOccurred())
+
+		session, authorizationURL := startBrowserLoginWith(browser)
+		Expect(authorizationURL.Host).To(Equal(authServer.Addr()))
+		Expect(session.Out.Contents()).To(ContainSubstring("Your browser has been opened to log in."))
+
+		resp, err := http.Get(authorizationURL.Query().Get("redirect_uri") + "?error=access_denied&state=" + url.QueryEscape(authorizationURL.Query().Get("state")))
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+
+		Eventually(session).Should(Exit(1))
+	})
+
+	It("may not be combined with other credentials", func() {
+		session := runCommand("login", "--browser", "-u", "some-user")
+
+		Eventually(session).Should(Exit(1))
+		Expect(session.Err).To(Say("Client, password, SSO and/or SSO passcode credentials may not be combined."))
+	})
+})
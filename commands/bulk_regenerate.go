@@ -1,18 +1,120 @@
 package commands
 
+import (
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/errors"
+)
+
 type BulkRegenerateCommand struct {
 	SignedBy   string `required:"yes" long:"signed-by" description:"Selects the credential whose children should recursively be regenerated"`
+	DryRun     bool   `long:"dry-run" description:"List the certificates which would be regenerated, with their expiry dates and depth below the CA, without regenerating them"`
+	Report     bool   `long:"report" description:"Report the expiry dates of the regenerated certificates before and after regenerating them"`
 	OutputJSON bool   `short:"j" long:"output-json" description:"Return response in JSON format"`
 	ClientCommand
 }
 
+// BulkRegenerateCertificate is a certificate signed by the CA, directly at depth 1 or indirectly below it
+type BulkRegenerateCertificate struct {
+	Name          string `json:"name" yaml:"name"`
+	Depth         int    `json:"depth" yaml:"depth"`
+	ExpiryDate    string `json:"expiry_date,omitempty" yaml:"expiry_date,omitempty"`
+	OldExpiryDate string `json:"old_expiry_date,omitempty" yaml:"old_expiry_date,omitempty"`
+	NewExpiryDate string `json:"new_expiry_date,omitempty" yaml:"new_expiry_date,omitempty"`
+}
+
 func (c *BulkRegenerateCommand) Execute([]string) error {
-	credentials, err := c.client.BulkRegenerate(c.SignedBy)
+	if c.DryRun && c.Report {
+		return errors.NewDryRunAndReportError()
+	}
+
+	if c.DryRun {
+		return c.dryRun()
+	}
+
+	var before []credentials.CertificateMetadata
+	if c.Report {
+		var err error
+		if before, err = c.client.GetAllCertificatesMetadata(); err != nil {
+			return err
+		}
+	}
+
+	results, err := c.client.BulkRegenerate(c.SignedBy)
+	if err != nil {
+		return err
+	}
+
+	if !c.Report {
+		formatOutput(c.OutputJSON, results)
+		return nil
+	}
+
+	after, err := c.client.GetAllCertificatesMetadata()
+	if err != nil {
+		return err
+	}
+
+	planned, _ := regenerationPlan(before, c.SignedBy)
+	depths := map[string]int{}
+	for _, certificate := range planned {
+		depths[certificate.Name] = certificate.Depth
+	}
+	oldExpiryDates := expiryDates(before)
+	newExpiryDates := expiryDates(after)
+
+	report := []BulkRegenerateCertificate{}
+	for _, name := range results.Certificates {
+		report = append(report, BulkRegenerateCertificate{
+			Name:          name,
+			Depth:         depths[name],
+			OldExpiryDate: oldExpiryDates[name],
+			NewExpiryDate: newExpiryDates[name],
+		})
+	}
+
+	formatOutput(c.OutputJSON, map[string][]BulkRegenerateCertificate{"regenerated_certificates": report})
+	return nil
+}
+
+func (c *BulkRegenerateCommand) dryRun() error {
+	certificates, err := c.client.GetAllCertificatesMetadata()
 	if err != nil {
 		return err
 	}
 
-	formatOutput(c.OutputJSON, credentials)
+	planned, ok := regenerationPlan(certificates, c.SignedBy)
+	if !ok {
+		return errors.NewUnknownCertificateError(c.SignedBy)
+	}
 
+	formatOutput(c.OutputJSON, map[string][]BulkRegenerateCertificate{"certificates_to_regenerate": planned})
 	return nil
 }
+
+// regenerationPlan returns the certificates which the CA signs, directly or indirectly, breadth first as
+// bulk-regenerate regenerates them, which are those certificates tree --root shows. It returns false when
+// the CA is not one of the certificates.
+func regenerationPlan(certificates []credentials.CertificateMetadata, signedBy string) ([]BulkRegenerateCertificate, bool) {
+	ca := findCertificateNode(certificateTree(certificates), certificateName(signedBy))
+	if ca == nil {
+		return nil, false
+	}
+
+	planned := []BulkRegenerateCertificate{}
+	for _, certificate := range certificatesSignedBy(ca) {
+		signing, _ := signingVersion(certificate.Versions)
+		planned = append(planned, BulkRegenerateCertificate{Name: certificate.Name, Depth: certificate.Depth, ExpiryDate: signing.ExpiryDate})
+	}
+
+	return planned, true
+}
+
+// expiryDates returns the expiry date of the signing version of each certificate by name
+func expiryDates(certificates []credentials.CertificateMetadata) map[string]string {
+	dates := map[string]string{}
+	for _, certificate := range certificates {
+		signing, _ := signingVersion(certificate.Versions)
+		dates[certificate.Name] = signing.ExpiryDate
+	}
+	return dates
+}
@@ -1,7 +1,9 @@
 package commands_test
 
 import (
+	"encoding/json"
 	"net/http"
+	"strings"
 
 	"code.cloudfoundry.org/credhub-cli/commands"
 	. "github.com/onsi/ginkgo/v2"
@@ -14,6 +16,46 @@ import (
 const BULK_REGENERATE_CREDENTIAL_REQUEST_JSON = `{"signed_by": "example-ca"}`
 const RESPONSE_JSON = `{"regenerated_credentials":["cert1","cert2","cert3"]}`
 
+// CERTIFICATE_SIGNS_MISMATCH_JSON has a CA whose signs list is out of date with the signed_by of the certificates
+const CERTIFICATE_SIGNS_MISMATCH_JSON = `{"certificates": [
+	{
+		"id": "ca-id",
+		"name": "/ca",
+		"signed_by": "/ca",
+		"signs": ["/moved-leaf"],
+		"versions": [
+			{"id": "ca-version", "expiry_date": "2035-01-01T00:00:00Z", "transitional": false, "certificate_authority": true, "self_signed": true}
+		]
+	},
+	{
+		"id": "other-ca-id",
+		"name": "/other-ca",
+		"signed_by": "/other-ca",
+		"signs": [],
+		"versions": [
+			{"id": "other-ca-version", "expiry_date": "2035-01-01T00:00:00Z", "transitional": false, "certificate_authority": true, "self_signed": true}
+		]
+	},
+	{
+		"id": "moved-leaf-id",
+		"name": "/moved-leaf",
+		"signed_by": "/other-ca",
+		"signs": [],
+		"versions": [
+			{"id": "moved-leaf-version", "expiry_date": "2030-01-01T00:00:00Z", "transitional": false, "certificate_authority": false, "self_signed": false}
+		]
+	},
+	{
+		"id": "new-leaf-id",
+		"name": "/new-leaf",
+		"signed_by": "/ca",
+		"signs": [],
+		"versions": [
+			{"id": "new-leaf-version", "expiry_date": "2030-01-01T00:00:00Z", "transitional": false, "certificate_authority": false, "self_signed": false}
+		]
+	}
+]}`
+
 var _ = Describe("Bulk-regenerate", func() {
 	BeforeEach(func() {
 		login()
@@ -79,6 +121,118 @@ var _ = Describe("Bulk-regenerate", func() {
 		})
 	})
 
+	Describe("Listing the certificates which would be regenerated", func() {
+		BeforeEach(func() {
+			server.RouteToHandler("GET", "/api/v1/certificates/",
+				RespondWith(http.StatusOK, CERTIFICATE_TREE_JSON),
+			)
+		})
+
+		It("prints the certificates signed by the CA with their depth and expiry date", func() {
+			session := runCommand("bulk-regenerate", "--signed-by", "root", "--dry-run")
+
+			Eventually(session).Should(Exit(0))
+			Expect(string(session.Out.Contents())).To(Equal(`certificates_to_regenerate:
+    - name: /intermediate
+      depth: 1
+      expiry_date: "2031-01-01T00:00:00Z"
+    - name: /other-leaf
+      depth: 1
+      expiry_date: "2030-01-01T00:00:00Z"
+    - name: /leaf
+      depth: 2
+      expiry_date: "2030-01-01T00:00:00Z"
+
+`))
+			Expect(server.ReceivedRequests()).NotTo(ContainElement(HaveField("Method", "POST")))
+		})
+
+		It("lists the certificates which certificates tree shows below the CA", func() {
+			server.RouteToHandler("GET", "/api/v1/certificates/",
+				RespondWith(http.StatusOK, CERTIFICATE_SIGNS_MISMATCH_JSON),
+			)
+
+			session := runCommand("certificates", "tree", "--root", "/ca", "-o", "json")
+			Eventually(session).Should(Exit(0))
+			var tree struct {
+				Certificates []struct {
+					Signs []struct {
+						Name string `json:"name"`
+					} `json:"signs"`
+				} `json:"certificates"`
+			}
+			Expect(json.Unmarshal(session.Out.Contents(), &tree)).To(Succeed())
+			var treeNames []string
+			for _, signed := range tree.Certificates[0].Signs {
+				treeNames = append(treeNames, signed.Name)
+			}
+
+			session = runCommand("bulk-regenerate", "--signed-by", "/ca", "--dry-run", "-j")
+			Eventually(session).Should(Exit(0))
+			var plan struct {
+				Certificates []commands.BulkRegenerateCertificate `json:"certificates_to_regenerate"`
+			}
+			Expect(json.Unmarshal(session.Out.Contents(), &plan)).To(Succeed())
+			var planNames []string
+			for _, certificate := range plan.Certificates {
+				planNames = append(planNames, certificate.Name)
+			}
+
+			Expect(treeNames).To(Equal([]string{"/new-leaf"}))
+			Expect(planNames).To(Equal(treeNames))
+		})
+
+		It("prints an empty list for a certificate which signs none", func() {
+			session := runCommand("bulk-regenerate", "--signed-by", "/leaf", "--dry-run", "-j")
+
+			Eventually(session).Should(Exit(0))
+			Expect(string(session.Out.Contents())).To(MatchJSON(`{"certificates_to_regenerate": []}`))
+		})
+
+		It("fails when the CA does not exist", func() {
+			session := runCommand("bulk-regenerate", "--signed-by", "/unknown", "--dry-run")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("The certificate '/unknown' could not be found."))
+		})
+
+		It("cannot be combined with a report", func() {
+			session := runCommand("bulk-regenerate", "--signed-by", "/root", "--dry-run", "--report")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("The --dry-run flag and --report flag are incompatible."))
+		})
+	})
+
+	Describe("Reporting the regenerated certificates", func() {
+		It("prints the old and new expiry dates of each regenerated certificate", func() {
+			regenerated := strings.NewReplacer(
+				"2030-01-01T00:00:00Z", "2036-01-01T00:00:00Z",
+				"2031-01-01T00:00:00Z", "2037-01-01T00:00:00Z",
+			).Replace(CERTIFICATE_TREE_JSON)
+			responses := []string{CERTIFICATE_TREE_JSON, regenerated}
+			server.RouteToHandler("GET", "/api/v1/certificates/", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(responses[0]))
+				responses = responses[1:]
+			})
+			server.RouteToHandler("POST", "/api/v1/bulk-regenerate",
+				CombineHandlers(
+					VerifyJSON(`{"signed_by": "/root"}`),
+					RespondWith(http.StatusOK, `{"regenerated_credentials": ["/intermediate", "/other-leaf", "/leaf"]}`),
+				),
+			)
+
+			session := runCommand("bulk-regenerate", "--signed-by", "/root", "--report", "-j")
+
+			Eventually(session).Should(Exit(0))
+			Expect(string(session.Out.Contents())).To(MatchJSON(`{"regenerated_certificates": [
+	{"name": "/intermediate", "depth": 1, "old_expiry_date": "2031-01-01T00:00:00Z", "new_expiry_date": "2037-01-01T00:00:00Z"},
+	{"name": "/other-leaf", "depth": 1, "old_expiry_date": "2030-01-01T00:00:00Z", "new_expiry_date": "2036-01-01T00:00:00Z"},
+	{"name": "/leaf", "depth": 2, "old_expiry_date": "2030-01-01T00:00:00Z", "new_expiry_date": "2036-01-01T00:00:00Z"}
+]}`))
+		})
+	})
+
 	Describe("help", func() {
 		It("Behaves like help", func() {
 			session := runCommand("bulk-regenerate", "-h")
@@ -0,0 +1,259 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/errors"
+)
+
+type CertificatesTreeCommand struct {
+	Root       string `long:"root" value-name:"CA" description:"Only show the certificates signed by the CA, directly or indirectly"`
+	Output     string `short:"o" long:"output" choice:"tree" choice:"dot" choice:"json" default:"tree" description:"Output format"`
+	OutputJSON bool   `short:"j" long:"output-json" description:"Return response in JSON format"`
+	ClientCommand
+}
+
+// certificateNode is a certificate with the certificates it signs
+type certificateNode struct {
+	Name     string                                   `json:"name"`
+	Id       string                                   `json:"id"`
+	SignedBy string                                   `json:"signed_by"`
+	Versions []credentials.CertificateMetadataVersion `json:"versions"`
+	Signs    []*certificateNode                       `json:"signs"`
+}
+
+func (c *CertificatesTreeCommand) Execute([]string) error {
+	certificates, err := c.client.GetAllCertificatesMetadata()
+	if err != nil {
+		return err
+	}
+
+	roots := certificateTree(certificates)
+	if c.Root != "" {
+		root := findCertificateNode(roots, certificateName(c.Root))
+		if root == nil {
+			return errors.NewUnknownCertificateError(c.Root)
+		}
+		roots = []*certificateNode{root}
+	}
+
+	switch {
+	case c.OutputJSON || c.Output == "json":
+		formatOutput(true, map[string][]*certificateNode{"certificates": roots})
+	case c.Output == "dot":
+		fmt.Print(certificateTreeDOT(roots))
+	default:
+		fmt.Print(certificateTreeText(roots))
+		if c.Root != "" {
+			fmt.Printf("\n%s signs %s, directly or indirectly.\n", roots[0].Name, pluralize(len(certificatesSignedBy(roots[0])), "certificate"))
+		}
+	}
+	return nil
+}
+
+// certificateTree arranges the certificates under the CAs which signed them. Certificates which are
+// self-signed, or whose CA is not stored in CredHub, are the roots of the tree.
+func certificateTree(certificates []credentials.CertificateMetadata) []*certificateNode {
+	sorted := make([]credentials.CertificateMetadata, len(certificates))
+	copy(sorted, certificates)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	nodes := map[string]*certificateNode{}
+	for _, certificate := range sorted {
+		nodes[certificate.Name] = &certificateNode{
+			Name:     certificate.Name,
+			Id:       certificate.Id,
+			SignedBy: certificate.SignedBy,
+			Versions: certificate.Versions,
+			Signs:    []*certificateNode{},
+		}
+	}
+
+	signs := map[string][]string{}
+	var roots []string
+	for _, certificate := range sorted {
+		if _, ok := nodes[certificate.SignedBy]; ok && certificate.SignedBy != certificate.Name {
+			signs[certificate.SignedBy] = append(signs[certificate.SignedBy], certificate.Name)
+		} else {
+			roots = append(roots, certificate.Name)
+		}
+	}
+
+	tree := []*certificateNode{}
+	attached := map[string]bool{}
+	var attach func(name string) *certificateNode
+	attach = func(name string) *certificateNode {
+		attached[name] = true
+		node := nodes[name]
+		for _, signed := range signs[name] {
+			if !attached[signed] {
+				node.Signs = append(node.Signs, attach(signed))
+			}
+		}
+		return node
+	}
+	for _, name := range roots {
+		tree = append(tree, attach(name))
+	}
+
+	// Certificates which sign each other in a cycle are not reachable from any root
+	for _, certificate := range sorted {
+		if !attached[certificate.Name] {
+			tree = append(tree, attach(certificate.Name))
+		}
+	}
+
+	return tree
+}
+
+func findCertificateNode(nodes []*certificateNode, name string) *certificateNode {
+	for _, node := range nodes {
+		if node.Name == name {
+			return node
+		}
+		if found := findCertificateNode(node.Signs, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// signedCertificate is a certificate below a CA in the tree, at depth 1 when the CA signs it directly
+type signedCertificate struct {
+	*certificateNode
+	Depth int
+}
+
+// certificatesSignedBy returns the certificates below the CA in the tree, breadth first. These are the
+// certificates which bulk-regenerate --signed-by regenerates, in the order it regenerates them.
+func certificatesSignedBy(ca *certificateNode) []signedCertificate {
+	signed := []signedCertificate{}
+	queue := []signedCertificate{{ca, 0}}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+
+		for _, node := range next.Signs {
+			certificate := signedCertificate{node, next.Depth + 1}
+			signed = append(signed, certificate)
+			queue = append(queue, certificate)
+		}
+	}
+	return signed
+}
+
+// certificateName adds the leading slash which CredHub adds to names given without one
+func certificateName(name string) string {
+	if !strings.HasPrefix(name, "/") {
+		return "/" + name
+	}
+	return name
+}
+
+func certificateTreeText(roots []*certificateNode) string {
+	var b strings.Builder
+	var write func(node *certificateNode, prefix, childPrefix string)
+	write = func(node *certificateNode, prefix, childPrefix string) {
+		fmt.Fprintf(&b, "%s%s", prefix, node.Name)
+		if markers := certificateMarkers(node, prefix == ""); len(markers) > 0 {
+			fmt.Fprintf(&b, "  (%s)", strings.Join(markers, ", "))
+		}
+		b.WriteString("\n")
+
+		for i, signed := range node.Signs {
+			if i == len(node.Signs)-1 {
+				write(signed, childPrefix+"└── ", childPrefix+"    ")
+			} else {
+				write(signed, childPrefix+"├── ", childPrefix+"│   ")
+			}
+		}
+	}
+
+	for _, root := range roots {
+		write(root, "", "")
+	}
+	return b.String()
+}
+
+// signingVersion returns the version of a certificate which signs other certificates, its latest version
+// which is not transitional, and whether it has a transitional version
+func signingVersion(versions []credentials.CertificateMetadataVersion) (credentials.CertificateMetadataVersion, bool) {
+	if len(versions) == 0 {
+		return credentials.CertificateMetadataVersion{}, false
+	}
+
+	signing := versions[0]
+	transitional := false
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].Transitional {
+			transitional = true
+		} else {
+			signing = versions[i]
+		}
+	}
+	return signing, transitional
+}
+
+// certificateMarkers describes the certificate by its signing version. The CA of a root which is not
+// self-signed is not in the tree, so it is named.
+func certificateMarkers(node *certificateNode, root bool) []string {
+	signing, transitional := signingVersion(node.Versions)
+
+	var markers []string
+	if signing.CertificateAuthority {
+		markers = append(markers, "CA")
+	}
+	if signing.SelfSigned {
+		markers = append(markers, "self-signed")
+	} else if root && node.SignedBy != "" {
+		markers = append(markers, "signed by "+node.SignedBy)
+	}
+	if signing.ExpiryDate != "" {
+		markers = append(markers, fmt.Sprintf("expires %s, %s days left", signing.ExpiryDate, daysLeft(signing.ExpiryDate)))
+	}
+	if transitional {
+		markers = append(markers, "transitional version")
+	}
+	return markers
+}
+
+func certificateTreeDOT(roots []*certificateNode) string {
+	var b strings.Builder
+	b.WriteString("digraph certificates {\n")
+
+	var write func(node *certificateNode, root bool)
+	write = func(node *certificateNode, root bool) {
+		signing, transitional := signingVersion(node.Versions)
+		attributes := []string{"label=" + strconv.Quote(strings.Join(append([]string{node.Name}, certificateMarkers(node, root)...), "\n"))}
+		if signing.CertificateAuthority {
+			attributes = append(attributes, "shape=box")
+		}
+		if transitional {
+			attributes = append(attributes, "style=dashed")
+		}
+		fmt.Fprintf(&b, "\t%s [%s];\n", strconv.Quote(node.Name), strings.Join(attributes, " "))
+
+		for _, signed := range node.Signs {
+			fmt.Fprintf(&b, "\t%s -> %s;\n", strconv.Quote(node.Name), strconv.Quote(signed.Name))
+			write(signed, false)
+		}
+	}
+
+	for _, root := range roots {
+		write(root, true)
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func pluralize(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
@@ -0,0 +1,138 @@
+package commands_test
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+const CERTIFICATE_TREE_JSON = `{"certificates": [
+	{
+		"id": "leaf-id",
+		"name": "/leaf",
+		"signed_by": "/intermediate",
+		"signs": [],
+		"versions": [
+			{"id": "leaf-version", "expiry_date": "2030-01-01T00:00:00Z", "transitional": false, "certificate_authority": false, "self_signed": false}
+		]
+	},
+	{
+		"id": "root-id",
+		"name": "/root",
+		"signed_by": "/root",
+		"signs": ["/intermediate", "/other-leaf"],
+		"versions": [
+			{"id": "root-version", "expiry_date": "2035-01-01T00:00:00Z", "transitional": false, "certificate_authority": true, "self_signed": true}
+		]
+	},
+	{
+		"id": "intermediate-id",
+		"name": "/intermediate",
+		"signed_by": "/root",
+		"signs": ["/leaf"],
+		"versions": [
+			{"id": "intermediate-new-version", "expiry_date": "2034-01-01T00:00:00Z", "transitional": true, "certificate_authority": true, "self_signed": false},
+			{"id": "intermediate-version", "expiry_date": "2031-01-01T00:00:00Z", "transitional": false, "certificate_authority": true, "self_signed": false}
+		]
+	},
+	{
+		"id": "other-leaf-id",
+		"name": "/other-leaf",
+		"signed_by": "/root",
+		"signs": [],
+		"versions": [
+			{"id": "other-leaf-version", "expiry_date": "2030-01-01T00:00:00Z", "transitional": false, "certificate_authority": false, "self_signed": false}
+		]
+	},
+	{
+		"id": "external-id",
+		"name": "/external",
+		"signed_by": "/external-ca",
+		"signs": [],
+		"versions": [
+			{"id": "external-version", "expiry_date": "2030-01-01T00:00:00Z", "transitional": false, "certificate_authority": false, "self_signed": false}
+		]
+	}
+]}`
+
+var _ = Describe("Certificates tree", func() {
+	BeforeEach(func() {
+		login()
+
+		server.RouteToHandler("GET", "/api/v1/certificates/",
+			RespondWith(http.StatusOK, CERTIFICATE_TREE_JSON),
+		)
+	})
+
+	ItRequiresAuthentication("certificates", "tree")
+	ItRequiresAnAPIToBeSet("certificates", "tree")
+
+	It("shows the certificates under the CAs which signed them", func() {
+		session := runCommand("certificates", "tree")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say(`/external  \(signed by /external-ca, expires 2030-01-01T00:00:00Z, \d+ days left\)\n`))
+		Expect(session.Out).To(Say(`/root  \(CA, self-signed, expires 2035-01-01T00:00:00Z, \d+ days left\)\n`))
+		Expect(session.Out).To(Say(`├── /intermediate  \(CA, expires 2031-01-01T00:00:00Z, \d+ days left, transitional version\)\n`))
+		Expect(session.Out).To(Say(`│   └── /leaf  \(expires 2030-01-01T00:00:00Z, \d+ days left\)\n`))
+		Expect(session.Out).To(Say(`└── /other-leaf  \(expires 2030-01-01T00:00:00Z, \d+ days left\)\n`))
+	})
+
+	It("shows the certificates signed by a CA and counts them", func() {
+		session := runCommand("certificates", "tree", "--root", "intermediate")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say(`^/intermediate  \(CA, signed by /root, `))
+		Expect(session.Out).To(Say(`└── /leaf`))
+		Expect(session.Out).To(Say(`/intermediate signs 1 certificate, directly or indirectly.`))
+		Expect(session.Out.Contents()).NotTo(ContainSubstring("/other-leaf"))
+	})
+
+	It("fails when the CA does not exist", func() {
+		session := runCommand("certificates", "tree", "--root", "/unknown")
+
+		Eventually(session).Should(Exit(1))
+		Expect(session.Err).To(Say("The certificate '/unknown' could not be found."))
+	})
+
+	It("renders the tree as Graphviz DOT", func() {
+		session := runCommand("certificates", "tree", "--root", "/root", "-o", "dot")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say(`digraph certificates \{\n`))
+		Expect(session.Out).To(Say(`\t"/root" \[label="/root\\nCA\\nself-signed\\nexpires 2035-01-01T00:00:00Z, \d+ days left" shape=box\];\n`))
+		Expect(session.Out).To(Say(`\t"/root" -> "/intermediate";\n`))
+		Expect(session.Out).To(Say(`\t"/intermediate" \[label="/intermediate\\nCA\\nexpires 2031-01-01T00:00:00Z, \d+ days left\\ntransitional version" shape=box style=dashed\];\n`))
+		Expect(session.Out).To(Say(`\t"/intermediate" -> "/leaf";\n`))
+		Expect(session.Out).To(Say(`\t"/root" -> "/other-leaf";\n`))
+		Expect(session.Out).To(Say(`\}\n`))
+	})
+
+	It("renders the tree as JSON", func() {
+		session := runCommand("certificates", "tree", "--root", "/intermediate", "-j")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out.Contents()).To(MatchJSON(`{"certificates": [{
+	"name": "/intermediate",
+	"id": "intermediate-id",
+	"signed_by": "/root",
+	"versions": [
+		{"id": "intermediate-new-version", "expiry_date": "2034-01-01T00:00:00Z", "transitional": true, "certificate_authority": true, "self_signed": false},
+		{"id": "intermediate-version", "expiry_date": "2031-01-01T00:00:00Z", "transitional": false, "certificate_authority": true, "self_signed": false}
+	],
+	"signs": [{
+		"name": "/leaf",
+		"id": "leaf-id",
+		"signed_by": "/intermediate",
+		"versions": [
+			{"id": "leaf-version", "expiry_date": "2030-01-01T00:00:00Z", "transitional": false, "certificate_authority": false, "self_signed": false}
+		],
+		"signs": []
+	}]
+}]}`))
+	})
+})
@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/values"
+	"code.cloudfoundry.org/credhub-cli/util"
+)
+
+type CertificatesVersionsCommand struct {
+	List   CertificateVersionsListCommand   `command:"list" description:"List the versions of a certificate" long-description:"List the versions of a certificate, newest first"`
+	Create CertificateVersionsCreateCommand `command:"create" description:"Add a version to a certificate" long-description:"Add a version with the provided value to a certificate, eg. a CA-signed certificate to distribute as a transitional version"`
+	Delete CertificateVersionsDeleteCommand `command:"delete" description:"Delete a version of a certificate" long-description:"Delete a version of a certificate. The last version of a certificate cannot be deleted."`
+}
+
+type CertificateVersionsListCommand struct {
+	Name    string `short:"n" long:"name" required:"yes" description:"Name of the certificate"`
+	Current bool   `long:"current" description:"Only list the active versions: the latest version and the transitional version"`
+	CertificatesOutput
+	ClientCommand
+}
+
+func (c *CertificateVersionsListCommand) Execute([]string) error {
+	certificate, err := c.client.GetCertificateMetadataByName(c.Name)
+	if err != nil {
+		return err
+	}
+
+	versions, err := c.client.GetCertificateVersions(certificate.Id, c.Current)
+	if err != nil {
+		return err
+	}
+
+	return printCertificateVersions(c.format(), versions)
+}
+
+type CertificateVersionsCreateCommand struct {
+	Name         string `short:"n" long:"name" required:"yes" description:"Name of the certificate"`
+	Root         string `short:"r" long:"root" description:"Sets the root CA from file or value"`
+	Certificate  string `short:"c" long:"certificate" required:"yes" description:"Sets the certificate from file or value"`
+	Private      string `short:"p" long:"private" description:"Sets the private key from file or value"`
+	Transitional bool   `long:"transitional" description:"Marks the version as transitional, so that it is trusted but does not sign certificates"`
+	CertificatesOutput
+	ClientCommand
+}
+
+func (c *CertificateVersionsCreateCommand) Execute([]string) error {
+	var value values.Certificate
+	var err error
+	if value.Ca, err = util.ReadFileOrStringFromField(c.Root); err != nil {
+		return err
+	}
+	if value.Certificate, err = util.ReadFileOrStringFromField(c.Certificate); err != nil {
+		return err
+	}
+	if value.PrivateKey, err = util.ReadFileOrStringFromField(c.Private); err != nil {
+		return err
+	}
+
+	certificate, err := c.client.GetCertificateMetadataByName(c.Name)
+	if err != nil {
+		return err
+	}
+
+	version, err := c.client.CreateCertificateVersion(certificate.Id, value, c.Transitional)
+	if err != nil {
+		return err
+	}
+
+	return printCertificateVersions(c.format(), []credentials.CertificateVersion{version})
+}
+
+type CertificateVersionsDeleteCommand struct {
+	Name      string `short:"n" long:"name" required:"yes" description:"Name of the certificate"`
+	VersionID string `long:"id" required:"yes" description:"ID of the version to delete"`
+	ClientCommand
+}
+
+func (c *CertificateVersionsDeleteCommand) Execute([]string) error {
+	certificate, err := c.client.GetCertificateMetadataByName(c.Name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.client.DeleteCertificateVersion(certificate.Id, c.VersionID); err != nil {
+		return err
+	}
+
+	fmt.Println("Certificate version successfully deleted")
+	return nil
+}
+
+// printCertificateVersions prints the versions as a table, or as JSON or YAML with their private keys redacted
+func printCertificateVersions(format string, versions []credentials.CertificateVersion) error {
+	if format != "table" {
+		for i := range versions {
+			if versions[i].Value.PrivateKey != "" {
+				versions[i].Value.PrivateKey = "<redacted>"
+			}
+		}
+		formatOutput(format == "json", map[string][]credentials.CertificateVersion{"versions": versions})
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION ID\tCREATED\tEXPIRES\tDAYS LEFT\tCA\tSELF-SIGNED\tTRANSITIONAL")
+	for _, version := range versions {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", version.Id, version.VersionCreatedAt, version.ExpiryDate, daysLeft(version.ExpiryDate), yesNo(version.CertificateAuthority), yesNo(version.SelfSigned), yesNo(version.Transitional))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,132 @@
+package commands_test
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+const CERTIFICATE_VERSIONS_JSON = `[
+	{
+		"id": "ca-new-version",
+		"name": "/ca",
+		"type": "certificate",
+		"value": {"ca": "new-ca", "certificate": "new-ca", "private_key": "new-private-key"},
+		"version_created_at": "2026-01-02T00:00:00Z",
+		"expiry_date": "2031-01-01T00:00:00Z",
+		"transitional": true,
+		"certificate_authority": true,
+		"self_signed": true
+	},
+	{
+		"id": "ca-version",
+		"name": "/ca",
+		"type": "certificate",
+		"value": {"ca": "old-ca", "certificate": "old-ca", "private_key": "old-private-key"},
+		"version_created_at": "2026-01-01T00:00:00Z",
+		"expiry_date": "2030-01-01T00:00:00Z",
+		"transitional": false,
+		"certificate_authority": true,
+		"self_signed": true
+	}
+]`
+
+var _ = Describe("Certificate versions", func() {
+	BeforeEach(func() {
+		login()
+
+		server.RouteToHandler("GET", "/api/v1/certificates/",
+			CombineHandlers(
+				VerifyRequest("GET", "/api/v1/certificates/", "name=%2Fca"),
+				RespondWith(http.StatusOK, CA_METADATA_JSON),
+			),
+		)
+	})
+
+	ItRequiresAuthentication("certificates", "versions", "list", "-n", "/ca")
+	ItRequiresAnAPIToBeSet("certificates", "versions", "list", "-n", "/ca")
+
+	Describe("list", func() {
+		It("lists the versions of the certificate in a table", func() {
+			server.RouteToHandler("GET", "/api/v1/certificates/ca-id/versions",
+				RespondWith(http.StatusOK, CERTIFICATE_VERSIONS_JSON),
+			)
+
+			session := runCommand("certificates", "versions", "list", "-n", "/ca")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say(`VERSION ID\s+CREATED\s+EXPIRES\s+DAYS LEFT\s+CA\s+SELF-SIGNED\s+TRANSITIONAL`))
+			Expect(session.Out).To(Say(`ca-new-version\s+2026-01-02T00:00:00Z\s+2031-01-01T00:00:00Z\s+\d+\s+yes\s+yes\s+yes`))
+			Expect(session.Out).To(Say(`ca-version\s+2026-01-01T00:00:00Z\s+2030-01-01T00:00:00Z\s+\d+\s+yes\s+yes\s+no`))
+		})
+
+		It("lists the current versions with their private keys redacted", func() {
+			server.RouteToHandler("GET", "/api/v1/certificates/ca-id/versions",
+				CombineHandlers(
+					VerifyRequest("GET", "/api/v1/certificates/ca-id/versions", "current=true"),
+					RespondWith(http.StatusOK, CERTIFICATE_VERSIONS_JSON),
+				),
+			)
+
+			session := runCommand("certificates", "versions", "list", "-n", "/ca", "--current", "-o", "yaml")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say("versions:"))
+			Expect(session.Out).To(Say("id: ca-new-version"))
+			Expect(session.Out).To(Say("private_key: <redacted>"))
+			Expect(session.Out).NotTo(Say("private-key"))
+		})
+	})
+
+	Describe("create", func() {
+		It("creates a transitional version", func() {
+			server.RouteToHandler("POST", "/api/v1/certificates/ca-id/versions",
+				CombineHandlers(
+					VerifyJSON(`{"value": {"ca": "new-ca", "certificate": "new-ca", "private_key": "new-private-key"}, "transitional": true}`),
+					RespondWith(http.StatusOK, `{"id": "ca-new-version", "name": "/ca", "type": "certificate", "value": {"private_key": "new-private-key"}, "transitional": true, "certificate_authority": true}`),
+				),
+			)
+
+			session := runCommand("certificates", "versions", "create", "-n", "/ca", "-r", "new-ca", "-c", "new-ca", "-p", "new-private-key", "--transitional", "-j")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say(`"id": "ca-new-version"`))
+			Expect(session.Out).To(Say(`"private_key": "<redacted>"`))
+		})
+
+		It("requires a certificate", func() {
+			session := runCommand("certificates", "versions", "create", "-n", "/ca")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("certificate"))
+		})
+	})
+
+	Describe("delete", func() {
+		It("deletes the version", func() {
+			server.RouteToHandler("DELETE", "/api/v1/certificates/ca-id/versions/ca-new-version",
+				RespondWith(http.StatusOK, `{"id": "ca-new-version", "name": "/ca", "type": "certificate"}`),
+			)
+
+			session := runCommand("certificates", "versions", "delete", "-n", "/ca", "--id", "ca-new-version")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say("Certificate version successfully deleted"))
+		})
+
+		It("reports the error of the server", func() {
+			server.RouteToHandler("DELETE", "/api/v1/certificates/ca-id/versions/ca-version",
+				RespondWith(http.StatusBadRequest, `{"error": "The minimum number of versions for a Certificate is 1."}`),
+			)
+
+			session := runCommand("certificates", "versions", "delete", "-n", "/ca", "--id", "ca-version")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("The minimum number of versions for a Certificate is 1."))
+		})
+	})
+})
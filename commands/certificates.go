@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/errors"
+)
+
+type CertificatesCommand struct {
+	List     CertificatesListCommand     `command:"list" description:"List certificates and their versions" long-description:"List the certificates and their active versions, with their expiry dates and the CAs which signed them. With --expiring-within, the command exits with an error when any certificate expires within the duration, which makes it usable as an expiry check."`
+	Show     CertificatesShowCommand     `command:"show" description:"Show a certificate and its versions" long-description:"Show a certificate, the CA which signed it, the certificates it signs and its active versions"`
+	Tree     CertificatesTreeCommand     `command:"tree" description:"Show the hierarchy of CAs and the certificates they sign" long-description:"Show the hierarchy of CAs and the certificates they sign as an indented tree, as Graphviz DOT or as JSON. With --root, only the certificates signed by the CA, directly or indirectly, are shown, which are those bulk-regenerate would regenerate."`
+	Versions CertificatesVersionsCommand `command:"versions" description:"List, create and delete the versions of a certificate" long-description:"List, create and delete the versions of a certificate, eg. to import a CA-signed certificate as a transitional version or to delete a version left behind by an aborted rotation"`
+}
+
+type CertificatesOutput struct {
+	Output     string `short:"o" long:"output" choice:"table" choice:"json" choice:"yaml" default:"table" description:"Output format"`
+	OutputJSON bool   `short:"j" long:"output-json" description:"Return response in JSON format"`
+}
+
+func (o CertificatesOutput) format() string {
+	if o.OutputJSON {
+		return "json"
+	}
+	return o.Output
+}
+
+type CertificatesListCommand struct {
+	ExpiringWithin string `long:"expiring-within" value-name:"DURATION" description:"Only list certificate versions which expire within the duration, eg. 30d or 12h, and exit with an error if there are any"`
+	CAOnly         bool   `long:"ca-only" description:"Only list certificate authorities"`
+	SelfSigned     bool   `long:"self-signed" description:"Only list self-signed certificates"`
+	Transitional   bool   `long:"transitional" description:"Only list transitional certificate versions"`
+	CertificatesOutput
+	ClientCommand
+}
+
+func (c *CertificatesListCommand) Execute([]string) error {
+	var expiresBefore time.Time
+	if c.ExpiringWithin != "" {
+		within, err := parseExpiryDuration(c.ExpiringWithin)
+		if err != nil {
+			return err
+		}
+		expiresBefore = time.Now().Add(within)
+	}
+
+	certificates, err := c.client.GetAllCertificatesMetadata()
+	if err != nil {
+		return err
+	}
+
+	listed := []credentials.CertificateMetadata{}
+	for _, certificate := range certificates {
+		versions := []credentials.CertificateMetadataVersion{}
+		for _, version := range certificate.Versions {
+			if c.CAOnly && !version.CertificateAuthority ||
+				c.SelfSigned && !version.SelfSigned ||
+				c.Transitional && !version.Transitional ||
+				!expiresBefore.IsZero() && !expiresBeforeTime(version, expiresBefore) {
+				continue
+			}
+			versions = append(versions, version)
+		}
+
+		if len(versions) > 0 {
+			certificate.Versions = versions
+			listed = append(listed, certificate)
+		}
+	}
+
+	if c.format() == "table" {
+		printCertificatesTable(listed)
+	} else {
+		formatOutput(c.format() == "json", map[string][]credentials.CertificateMetadata{"certificates": listed})
+	}
+
+	if !expiresBefore.IsZero() && len(listed) > 0 {
+		return errors.NewCertificatesExpiringError(len(listed), c.ExpiringWithin)
+	}
+	return nil
+}
+
+type CertificatesShowCommand struct {
+	Name string `short:"n" long:"name" required:"yes" description:"Name of the certificate"`
+	CertificatesOutput
+	ClientCommand
+}
+
+func (c *CertificatesShowCommand) Execute([]string) error {
+	certificate, err := c.client.GetCertificateMetadataByName(c.Name)
+	if err != nil {
+		return err
+	}
+
+	if c.format() != "table" {
+		formatOutput(c.format() == "json", certificate)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "name:\t%s\n", certificate.Name)
+	fmt.Fprintf(w, "id:\t%s\n", certificate.Id)
+	fmt.Fprintf(w, "signed by:\t%s\n", certificate.SignedBy)
+	fmt.Fprintf(w, "signs:\t%s\n", strings.Join(certificate.Signs, ", "))
+	w.Flush()
+	fmt.Println()
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION ID\tEXPIRES\tDAYS LEFT\tCA\tSELF-SIGNED\tTRANSITIONAL")
+	for _, version := range certificate.Versions {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", version.Id, version.ExpiryDate, daysLeft(version.ExpiryDate), yesNo(version.CertificateAuthority), yesNo(version.SelfSigned), yesNo(version.Transitional))
+	}
+	return w.Flush()
+}
+
+func printCertificatesTable(certificates []credentials.CertificateMetadata) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tEXPIRES\tDAYS LEFT\tCA\tSELF-SIGNED\tTRANSITIONAL\tSIGNED BY")
+	for _, certificate := range certificates {
+		for _, version := range certificate.Versions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", certificate.Name, version.ExpiryDate, daysLeft(version.ExpiryDate), yesNo(version.CertificateAuthority), yesNo(version.SelfSigned), yesNo(version.Transitional), certificate.SignedBy)
+		}
+	}
+	w.Flush()
+}
+
+// parseExpiryDuration parses a number of days such as 30d, or a duration such as 12h
+func parseExpiryDuration(duration string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(duration, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, errors.NewInvalidExpiryDurationError(duration)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(duration)
+	if err != nil || d < 0 {
+		return 0, errors.NewInvalidExpiryDurationError(duration)
+	}
+	return d, nil
+}
+
+func expiresBeforeTime(version credentials.CertificateMetadataVersion, t time.Time) bool {
+	expiry, err := time.Parse(time.RFC3339, version.ExpiryDate)
+	return err == nil && expiry.Before(t)
+}
+
+func daysLeft(expiryDate string) string {
+	expiry, err := time.Parse(time.RFC3339, expiryDate)
+	if err != nil {
+		return ""
+	}
+	return strconv.Itoa(int(math.Floor(time.Until(expiry).Hours() / 24)))
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
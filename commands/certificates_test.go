@@ -0,0 +1,142 @@
+package commands_test
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Certificates", func() {
+	var (
+		inAYear   string
+		inTenDays string
+	)
+
+	BeforeEach(func() {
+		login()
+
+		inAYear = time.Now().AddDate(1, 0, 1).UTC().Format(time.RFC3339)
+		inTenDays = time.Now().AddDate(0, 0, 10).Add(time.Hour).UTC().Format(time.RFC3339)
+
+		server.RouteToHandler("GET", "/api/v1/certificates/",
+			RespondWith(http.StatusOK, fmt.Sprintf(`{"certificates": [
+	{
+		"id": "ca-id",
+		"name": "/ca",
+		"signed_by": "/ca",
+		"signs": ["/leaf"],
+		"versions": [
+			{"id": "ca-new-version", "expiry_date": "%[1]s", "transitional": true, "certificate_authority": true, "self_signed": true},
+			{"id": "ca-version", "expiry_date": "%[1]s", "transitional": false, "certificate_authority": true, "self_signed": true}
+		]
+	},
+	{
+		"id": "leaf-id",
+		"name": "/leaf",
+		"signed_by": "/ca",
+		"signs": [],
+		"versions": [
+			{"id": "leaf-version", "expiry_date": "%[2]s", "transitional": false, "certificate_authority": false, "self_signed": false}
+		]
+	}
+]}`, inAYear, inTenDays)),
+		)
+	})
+
+	ItRequiresAuthentication("certificates", "list")
+	ItRequiresAnAPIToBeSet("certificates", "list")
+
+	Describe("list", func() {
+		It("lists the versions of the certificates in a table", func() {
+			session := runCommand("certificates", "list")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say(`NAME\s+EXPIRES\s+DAYS LEFT\s+CA\s+SELF-SIGNED\s+TRANSITIONAL\s+SIGNED BY`))
+			Expect(session.Out).To(Say(`/ca\s+` + inAYear + `\s+\d+\s+yes\s+yes\s+yes\s+/ca`))
+			Expect(session.Out).To(Say(`/ca\s+` + inAYear + `\s+\d+\s+yes\s+yes\s+no\s+/ca`))
+			Expect(session.Out).To(Say(`/leaf\s+` + inTenDays + `\s+10\s+no\s+no\s+no\s+/ca`))
+		})
+
+		It("filters certificate authorities and transitional versions", func() {
+			session := runCommand("certificates", "list", "--ca-only", "--transitional", "--output", "json")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out.Contents()).To(MatchJSON(fmt.Sprintf(`{"certificates": [{
+	"id": "ca-id",
+	"name": "/ca",
+	"signed_by": "/ca",
+	"signs": ["/leaf"],
+	"versions": [
+		{"id": "ca-new-version", "expiry_date": "%s", "transitional": true, "certificate_authority": true, "self_signed": true}
+	]
+}]}`, inAYear)))
+		})
+
+		It("fails when certificates expire within the duration", func() {
+			session := runCommand("certificates", "list", "--expiring-within", "30d", "-o", "yaml")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Out).To(Say(`name: /leaf`))
+			Expect(session.Out).NotTo(Say(`name: /ca`))
+			Expect(session.Err).To(Say("1 certificate expires within 30d."))
+		})
+
+		It("succeeds when no certificates expire within the duration", func() {
+			session := runCommand("certificates", "list", "--expiring-within", "240h")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).NotTo(Say(`/leaf`))
+		})
+
+		It("rejects an invalid duration", func() {
+			session := runCommand("certificates", "list", "--expiring-within", "a month")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("The duration 'a month' is not valid."))
+		})
+	})
+
+	Describe("show", func() {
+		BeforeEach(func() {
+			server.RouteToHandler("GET", "/api/v1/certificates/",
+				CombineHandlers(
+					VerifyRequest("GET", "/api/v1/certificates/", "name=/ca"),
+					RespondWith(http.StatusOK, fmt.Sprintf(`{"certificates": [{
+	"id": "ca-id",
+	"name": "/ca",
+	"signed_by": "/ca",
+	"signs": ["/leaf", "/other-leaf"],
+	"versions": [
+		{"id": "ca-version", "expiry_date": "%s", "transitional": false, "certificate_authority": true, "self_signed": true}
+	]
+}]}`, inAYear)),
+				),
+			)
+		})
+
+		It("shows a certificate and its versions", func() {
+			session := runCommand("certificates", "show", "-n", "/ca")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say(`name:\s+/ca`))
+			Expect(session.Out).To(Say(`id:\s+ca-id`))
+			Expect(session.Out).To(Say(`signed by:\s+/ca`))
+			Expect(session.Out).To(Say(`signs:\s+/leaf, /other-leaf`))
+			Expect(session.Out).To(Say(`VERSION ID\s+EXPIRES\s+DAYS LEFT\s+CA\s+SELF-SIGNED\s+TRANSITIONAL`))
+			Expect(session.Out).To(Say(`ca-version\s+` + inAYear + `\s+\d+\s+yes\s+yes\s+no`))
+		})
+
+		It("shows a certificate in JSON", func() {
+			session := runCommand("certificates", "show", "-n", "/ca", "-j")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say(`"name": "/ca"`))
+		})
+	})
+})
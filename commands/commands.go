@@ -20,13 +20,21 @@ type CredhubCommand struct {
 	Logout           LogoutCommand           `command:"logout"     alias:"o" description:"Discard authenticated user session" long-description:"Discard authenticated session. Refresh token revocation will be attempted for password grants."`
 	Regenerate       RegenerateCommand       `command:"regenerate" alias:"r" description:"Generate and set a credential value using the same attributes as the stored value" long-description:"Set a credential with a generated value using the same attributes as the stored value"`
 	BulkRegenerate   BulkRegenerateCommand   `command:"bulk-regenerate" description:"Recursively regenerate all certificates signed by the provided certificate" long-description:"Recursively regenerate all certificates signed by the provided certificate"`
+	Certificates     CertificatesCommand     `command:"certificates" description:"List and show certificates, their expiry dates and the CAs which signed them" long-description:"List and show certificates, their active versions, their expiry dates and the CAs which signed them"`
+	RotateCA         RotateCACommand         `command:"rotate-ca" description:"Rotate a certificate authority in steps through a transitional version" long-description:"Rotate a certificate authority without downtime, one step at a time, redeploying after each step.\n\nnew:   generate a new transitional version of the CA, which clients trust while the current version keeps signing certificates\nswap:  make the new version sign certificates, keep the previous version as transitional and regenerate the certificates signed by the CA\nclean: remove the transitional flag from the previous version, so that it is no longer trusted"`
 	Set              SetCommand              `command:"set"        alias:"s" description:"Set a credential with a provided value" long-description:"Set a credential with provided value(s). A type must be specified when setting a credential. The provided flags are used to set specific values of a credential, e.g. a certificate credential may use --root, --certificate and --private to set each value. Supported credential types are prefixed in the flag description."`
 	Curl             CurlCommand             `command:"curl"       description:"Make an arbitrary request to the targeted CredHub server." long-description:"Make an arbitrary request to the targeted CredHub server"`
 	SetPermission    SetPermissionCommand    `command:"set-permission" description:"Set permissions for an actor on a given path." long-description:"Set permissions for an actor on a given path"`
 	GetPermission    GetPermissionCommand    `command:"get-permission" description:"Get permissions for an actor on a given path." long-description:"Get permissions for an actor on a given path"`
 	DeletePermission DeletePermissionCommand `command:"delete-permission" description:"Delete permissions for an actor on a given path." long-description:"Delete permissions for an actor on a given path"`
+	TokenStore       TokenStoreCommand       `command:"token-store" description:"Get or set where access and refresh tokens are saved" long-description:"Get or set where access and refresh tokens are saved. The plaintext store keeps them in the config file. The encrypted-file store encrypts them with a key derived from the passphrase in CREDHUB_TOKEN_PASSPHRASE, or from a key file. Saved tokens are moved to the new store."`
+	Target           TargetCommand           `command:"target" description:"Manage the named CredHub servers which commands may be sent to" long-description:"Manage the named CredHub servers which commands may be sent to. Each target keeps its own API URL, trusted CAs and session, so switching between targets does not require logging in again."`
 
 	HttpTimeout *time.Duration `long:"http-timeout" env:"CREDHUB_HTTP_TIMEOUT" description:"Http timeout for http-client. Needs to have unit passed in (i.e. 30s, 1m)"`
+	LogFile     string         `long:"log-file" env:"CREDHUB_LOG_FILE" description:"Append debug output, including the API calls made by the command, to the given file instead of stderr"`
+	Retries     *int           `long:"retries" env:"CREDHUB_RETRIES" description:"Number of times to retry GET and DELETE requests that fail with a transient error (connection failure, 429, 502, 503 or 504)"`
+	RetryPut    bool           `long:"retry-put" env:"CREDHUB_RETRY_PUT" description:"Also retry PUT requests. A retried set or import may create an additional credential version if the first attempt reached the server"`
+	TargetName  string         `long:"target" env:"CREDHUB_TARGET" description:"Name of the target to send the command to, instead of the current target"`
 
 	Version func() `long:"version" description:"Version of CLI and targeted CredHub API"`
 	Token   func() `long:"token" description:"Return your current CredHub authentication token"`
@@ -35,10 +43,10 @@ type CredhubCommand struct {
 var CredHub CredhubCommand
 
 type ClientCommand struct {
-	client *credhub.CredHub
+	client credhub.Client
 }
 
-func (n *ClientCommand) SetClient(client *credhub.CredHub) {
+func (n *ClientCommand) SetClient(client credhub.Client) {
 	n.client = client
 }
 
@@ -0,0 +1,86 @@
+package commands_test
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/credhub-cli/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Device login", func() {
+	BeforeEach(func() {
+		server.RouteToHandler("GET", "/info",
+			RespondWith(http.StatusOK, `{
+				"app":{"name":"CredHub","version":"2.9.0"},
+				"auth-server":{"url":"`+authServer.URL()+`"}
+				}`),
+		)
+		authServer.RouteToHandler("GET", "/info", RespondWith(http.StatusOK, ""))
+		authServer.RouteToHandler("POST", "/oauth/device_authorization",
+			CombineHandlers(
+				VerifyFormKV("client_id", config.AuthClient),
+				RespondWith(http.StatusOK, `{
+					"device_code":"some-device-code",
+					"user_code":"WDJB-MJHT",
+					"verification_uri":"https://uaa.example.com/device",
+					"expires_in":1800,
+					"interval":1}`),
+			),
+		)
+	})
+
+	AfterEach(func() {
+		config.RemoveConfig()
+	})
+
+	It("prints the user code and saves the tokens once the login is completed", func() {
+		authServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest("POST", "/oauth/token"),
+				RespondWith(http.StatusBadRequest, `{"error":"authorization_pending"}`),
+			),
+			CombineHandlers(
+				VerifyRequest("POST", "/oauth/token"),
+				VerifyFormKV("grant_type", "urn:ietf:params:oauth:grant-type:device_code"),
+				VerifyFormKV("device_code", "some-device-code"),
+				RespondWith(http.StatusOK, `{
+					"access_token":"2YotnFZFEjr1zCsicMWpAA",
+					"refresh_token":"erousflkajqwer",
+					"token_type":"bearer",
+					"expires_in":3600}`),
+			),
+		)
+
+		session := runCommand("login", "--device")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say("To log in, visit https://uaa.example.com/device and enter the code WDJB-MJHT"))
+		Expect(session.Out).To(Say("Login Successful"))
+
+		cfg := config.ReadConfig()
+		Expect(cfg.AccessToken).To(Equal("2YotnFZFEjr1zCsicMWpAA"))
+		Expect(cfg.RefreshToken).To(Equal("erousflkajqwer"))
+	})
+
+	It("fails when the user denies the login", func() {
+		authServer.RouteToHandler("POST", "/oauth/token",
+			RespondWith(http.StatusBadRequest, `{"error":"access_denied","error_description":"The user denied the request"}`),
+		)
+
+		session := runCommand("login", "--device")
+
+		Eventually(session).Should(Exit(1))
+		Expect(session.Err).To(Say("access_denied The user denied the request"))
+	})
+
+	It("is not supported by OpenID Connect providers", func() {
+		session := runCommand("login", "--device", "--oidc")
+
+		Eventually(session).Should(Exit(1))
+		Expect(session.Err).To(Say("Device login is only supported by UAA."))
+	})
+})
@@ -0,0 +1,137 @@
+package commands_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Exec credential plugin", func() {
+	var (
+		plugin string
+		runs   string
+	)
+
+	// writePlugin writes a plugin which records each run and prints a token for its first argument, with expiresAt
+	writePlugin := func(expiresAt time.Time) {
+		script := "#!/bin/sh\necho run >> '" + runs + "'\n" +
+			"printf '{\"access_token\": \"token-for-%s\", \"expires_at\": \"" + expiresAt.Format(time.RFC3339) + "\"}' \"$1\"\n"
+		Expect(os.WriteFile(plugin, []byte(script), 0700)).To(Succeed())
+	}
+
+	runCount := func() int {
+		data, err := os.ReadFile(runs)
+		if os.IsNotExist(err) {
+			return 0
+		}
+		Expect(err).NotTo(HaveOccurred())
+		return strings.Count(string(data), "run")
+	}
+
+	BeforeEach(func() {
+		dir := GinkgoT().TempDir()
+		plugin = filepath.Join(dir, "credhub-token")
+		runs = filepath.Join(dir, "runs")
+		writePlugin(time.Now().Add(time.Hour))
+
+		server.RouteToHandler("GET", "/info",
+			RespondWith(http.StatusOK, `{
+				"app":{"name":"CredHub","version":"2.9.0"},
+				"auth-server":{"url":"`+authServer.URL()+`"}
+				}`),
+		)
+		authServer.RouteToHandler("GET", "/info", RespondWith(http.StatusOK, ""))
+	})
+
+	AfterEach(func() {
+		config.RemoveConfig()
+	})
+
+	It("saves the command on login and uses the tokens it prints", func() {
+		server.RouteToHandler("GET", "/api/v1/data",
+			CombineHandlers(
+				VerifyHeaderKV("Authorization", "Bearer token-for-credhub"),
+				RespondWith(http.StatusOK, `{"credentials": []}`),
+			),
+		)
+
+		session := runCommand("login", "--exec-command", plugin, "--exec-arg", "credhub")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say("Login Successful"))
+		Expect(authServer.ReceivedRequests()).To(BeEmpty())
+
+		cfg := config.ReadConfig()
+		Expect(cfg.ExecCommand).To(Equal([]string{plugin, "credhub"}))
+		Expect(cfg.AccessToken).To(Equal("token-for-credhub"))
+		Expect(cfg.AccessTokenExpiry).NotTo(BeNil())
+
+		session = runCommand("find")
+		Eventually(session).Should(Exit(0))
+
+		session = runCommand("--token")
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say("Bearer token-for-credhub"))
+		Expect(runCount()).To(Equal(1))
+
+		session = runCommand("logout")
+		Eventually(session).Should(Exit(0))
+		Expect(config.ReadConfig().ExecCommand).To(BeEmpty())
+		Expect(authServer.ReceivedRequests()).To(BeEmpty())
+	})
+
+	It("runs the command again and saves the new token when the saved one is about to expire", func() {
+		server.RouteToHandler("GET", "/api/v1/data", RespondWith(http.StatusOK, `{"credentials": []}`))
+		writePlugin(time.Now().Add(10 * time.Second))
+
+		session := runCommand("login", "--exec-command", plugin, "--exec-arg", "credhub")
+		Eventually(session).Should(Exit(0))
+
+		expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+		writePlugin(expiresAt)
+
+		session = runCommand("find")
+		Eventually(session).Should(Exit(0))
+		session = runCommand("find")
+		Eventually(session).Should(Exit(0))
+
+		Expect(runCount()).To(Equal(2))
+		cfg := config.ReadConfig()
+		Expect(cfg.AccessToken).To(Equal("token-for-credhub"))
+		Expect(*cfg.AccessTokenExpiry).To(BeTemporally("==", expiresAt))
+	})
+
+	It("does not save a command which fails", func() {
+		Expect(os.WriteFile(plugin, []byte("#!/bin/sh\necho 'not logged in to the broker' >&2\nexit 3\n"), 0700)).To(Succeed())
+
+		session := runCommand("login", "--exec-command", plugin)
+
+		Eventually(session).Should(Exit(1))
+		Expect(session.Err).To(Say("not logged in to the broker"))
+		Expect(session.Err).To(Say("the exec credential plugin .* failed: exit status 3"))
+		Expect(config.ReadConfig().ExecCommand).To(BeEmpty())
+	})
+
+	It("may not be combined with other credentials", func() {
+		session := runCommand("login", "--exec-command", plugin, "--client-name", "some-client")
+
+		Eventually(session).Should(Exit(1))
+		Expect(session.Err).To(Say("Client, password, SSO and/or SSO passcode credentials may not be combined."))
+	})
+
+	It("requires a command with its arguments", func() {
+		session := runCommand("login", "--exec-arg", "credhub")
+
+		Eventually(session).Should(Exit(1))
+		Expect(session.Err).To(Say("The exec command must be provided with its arguments."))
+	})
+})
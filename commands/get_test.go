@@ -2,12 +2,18 @@ package commands_test
 
 import (
 	"bytes"
+	"errors"
 	"net/http"
+	"os"
+	"path/filepath"
 
 	"runtime"
 
 	"fmt"
 
+	"code.cloudfoundry.org/credhub-cli/commands"
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gbytes"
@@ -55,6 +61,95 @@ var _ = Describe("Get", func() {
 		Expect(session.Err).To(Say("The --versions flag and --id flag are incompatible."))
 	})
 
+	Describe("retrying transient failures", func() {
+		dataRequests := func() int {
+			count := 0
+			for _, request := range server.ReceivedRequests() {
+				if request.URL.Path == "/api/v1/data" {
+					count++
+				}
+			}
+			return count
+		}
+
+		BeforeEach(func() {
+			responseJSON := fmt.Sprintf(arrayResponseJSON, "value", "my-value", `"potatoes"`, "null")
+
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest("GET", "/api/v1/data", "current=true&name=my-value"),
+					RespondWith(http.StatusServiceUnavailable, `{"error":"service unavailable"}`),
+				),
+				CombineHandlers(
+					VerifyRequest("GET", "/api/v1/data", "current=true&name=my-value"),
+					RespondWith(http.StatusOK, responseJSON),
+				),
+			)
+		})
+
+		It("retries the request when --retries is provided", func() {
+			session := runCommand("get", "-n", "my-value", "--retries", "1")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say("value: potatoes"))
+			Expect(dataRequests()).To(Equal(2))
+		})
+
+		It("retries the request when CREDHUB_RETRIES is set", func() {
+			session := runCommandWithEnv([]string{"CREDHUB_RETRIES=1"}, "get", "-n", "my-value")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say("value: potatoes"))
+			Expect(dataRequests()).To(Equal(2))
+		})
+
+		It("does not retry the request by default", func() {
+			session := runCommand("get", "-n", "my-value")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("service unavailable"))
+			Expect(dataRequests()).To(Equal(1))
+		})
+	})
+
+	Describe("debug output", func() {
+		BeforeEach(func() {
+			responseJSON := fmt.Sprintf(arrayResponseJSON, "password", "my-password", `"some-secret-password"`, "null")
+
+			server.RouteToHandler("GET", "/api/v1/data",
+				CombineHandlers(
+					VerifyRequest("GET", "/api/v1/data", "current=true&name=my-password"),
+					RespondWith(http.StatusOK, responseJSON),
+				),
+			)
+		})
+
+		It("writes the API calls to stderr with secrets redacted when CREDHUB_DEBUG is true", func() {
+			session := runCommandWithEnv([]string{"CREDHUB_DEBUG=true"}, "get", "-n", "my-password", "-q")
+
+			Eventually(session).Should(Exit(0))
+			Expect(string(session.Out.Contents())).To(Equal("some-secret-password\n"))
+			Expect(session.Err).To(Say(`\[DEBUG\] sending request method=GET`))
+			Expect(session.Err).To(Say("Authorization: <redacted>"))
+			Expect(string(session.Err.Contents())).NotTo(ContainSubstring("some-secret-password"))
+			Expect(string(session.Err.Contents())).NotTo(ContainSubstring("test-access-token"))
+		})
+
+		It("appends the API calls to the file given by --log-file", func() {
+			logFile := filepath.Join(homeDir, "credhub.log")
+
+			session := runCommand("get", "-n", "my-password", "-q", "--log-file", logFile)
+
+			Eventually(session).Should(Exit(0))
+			Expect(string(session.Err.Contents())).To(BeEmpty())
+
+			logs, err := os.ReadFile(logFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(logs)).To(ContainSubstring("GET /api/v1/data?current=true&name=my-password"))
+			Expect(string(logs)).NotTo(ContainSubstring("some-secret-password"))
+		})
+	})
+
 	Describe("getting a secret without metadata", func() {
 		Context("with --output-json flag", func() {
 			It("contains metadata in json output", func() {
@@ -892,3 +987,27 @@ private_key: |-
 		Eventually(session.Out).Should(Say(outStr + timestamp))
 	})
 })
+
+// fakeClient fails to read every credential, recording the name it was asked for
+type fakeClient struct {
+	credhub.Client
+	requestedName string
+}
+
+func (f *fakeClient) GetLatestVersion(name string) (credentials.Credential, error) {
+	f.requestedName = name
+	return credentials.Credential{}, errors.New("some error")
+}
+
+var _ = Describe("Get with a substituted client", func() {
+	It("reads the credential through the client it is given", func() {
+		client := &fakeClient{}
+		getCommand := commands.GetCommand{Name: "/some-credential"}
+		getCommand.SetClient(client)
+
+		err := getCommand.Execute([]string{})
+
+		Expect(err).To(MatchError("some error"))
+		Expect(client.requestedName).To(Equal("/some-credential"))
+	})
+})
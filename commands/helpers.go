@@ -2,37 +2,26 @@ package commands
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
 
 	"code.cloudfoundry.org/credhub-cli/config"
 	"code.cloudfoundry.org/credhub-cli/credhub"
-	"code.cloudfoundry.org/credhub-cli/credhub/auth"
 	"code.cloudfoundry.org/credhub-cli/errors"
+	"code.cloudfoundry.org/credhub-cli/util"
 	"go.yaml.in/yaml/v3"
 )
 
-func initializeCredhubClient(cfg config.Config) (*credhub.CredHub, error) {
-	var credhubClient *credhub.CredHub
-
-	readConfigFromEnvironmentVariables(&cfg)
-
-	err := config.ValidateConfig(cfg)
-	if err != nil {
-		if !clientCredentialsInEnvironment() || config.ValidateConfigApi(cfg) != nil {
-			return nil, err
-		}
+func initializeCredhubClient(cfg config.Config, options ...credhub.Option) (*credhub.CredHub, error) {
+	if err := readConfigFromEnvironmentVariables(&cfg); err != nil {
+		return nil, err
 	}
 
-	if clientCredentialsInEnvironment() {
-		credhubClient, err = newCredhubClient(&cfg, os.Getenv("CREDHUB_CLIENT"), os.Getenv("CREDHUB_SECRET"), true)
-	} else {
-		credhubClient, err = newCredhubClient(&cfg, config.AuthClient, config.AuthPassword, false)
-	}
-
-	return credhubClient, err
+	return cfg.Client(options...)
 }
 
 func formatOutput(outputJSON bool, v interface{}) {
@@ -50,6 +39,7 @@ func formatOutput(outputJSON bool, v interface{}) {
 	}
 }
 
+// readConfigFromEnvironmentVariables fills in the API and auth server of cfg from the CREDHUB_* environment variables
 func readConfigFromEnvironmentVariables(cfg *config.Config) error {
 	if cfg.CaCerts == nil && os.Getenv("CREDHUB_CA_CERT") != "" {
 		caCerts, err := ReadOrGetCaCerts([]string{os.Getenv("CREDHUB_CA_CERT")})
@@ -73,30 +63,36 @@ func readConfigFromEnvironmentVariables(cfg *config.Config) error {
 		cfg.AuthURL = credhubInfo.AuthServer.URL
 	}
 
-	return config.WriteConfig(*cfg)
+	// the config is not saved, since a config from the CREDHUB_* environment variables would replace the saved login
+	return nil
 }
 
-func newCredhubClient(cfg *config.Config, clientId string, clientSecret string, usingClientCredentials bool) (*credhub.CredHub, error) {
-	credhubClient, err := credhub.New(cfg.ApiURL, credhub.CaCerts(cfg.CaCerts...), credhub.SkipTLSValidation(cfg.InsecureSkipVerify), credhub.Auth(auth.Uaa(
-		clientId,
-		clientSecret,
-		"",
-		"",
-		cfg.AccessToken,
-		cfg.RefreshToken,
-		usingClientCredentials,
-	)),
-		credhub.AuthURL(cfg.AuthURL),
-		credhub.SetHttpTimeout(cfg.HttpTimeout))
-	return credhubClient, err
-}
+// clientCertificatePaths validates the client certificate and key given on the command line, and returns their
+// absolute paths so that the persisted config keeps pointing at them from any working directory
+func clientCertificatePaths(certFile, keyFile string) (string, string, error) {
+	if certFile == "" || keyFile == "" {
+		return "", "", errors.NewClientCertificateParametersError()
+	}
+
+	certPath, err := filepath.Abs(certFile)
+	if err != nil {
+		return "", "", err
+	}
+
+	keyPath, err := filepath.Abs(keyFile)
+	if err != nil {
+		return "", "", err
+	}
+
+	if _, err := tls.LoadX509KeyPair(certPath, keyPath); err != nil {
+		return "", "", errors.NewClientCertificateLoadError(err)
+	}
 
-func clientCredentialsInEnvironment() bool {
-	return os.Getenv("CREDHUB_CLIENT") != "" || os.Getenv("CREDHUB_SECRET") != ""
+	return certPath, keyPath, nil
 }
 
 func verifyAuthServerConnection(cfg config.Config, skipTlsValidation bool) error {
-	credhubClient, err := credhub.New(cfg.ApiURL, credhub.CaCerts(cfg.CaCerts...), credhub.SkipTLSValidation(skipTlsValidation), credhub.SetHttpTimeout(cfg.HttpTimeout))
+	credhubClient, err := credhub.New(cfg.ApiURL, credhub.CaCerts(cfg.CaCerts...), credhub.SkipTLSValidation(skipTlsValidation), credhub.SetHttpTimeout(cfg.HttpTimeout), credhub.Logger(util.DebugLogger()))
 	if err != nil {
 		return err
 	}
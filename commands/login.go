@@ -2,9 +2,13 @@ package commands
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"code.cloudfoundry.org/credhub-cli/config"
 	"code.cloudfoundry.org/credhub-cli/credhub"
@@ -25,6 +29,14 @@ type LoginCommand struct {
 	SkipTlsValidation bool     `long:"skip-tls-validation" description:"Skip certificate validation of the API endpoint. Not recommended!"`
 	SSO               bool     `long:"sso" description:"Prompt for a one-time passcode to login"`
 	SSOPasscode       string   `long:"sso-passcode" description:"One-time passcode"`
+	ClientCert        string   `long:"client-cert" description:"Client certificate for mutual TLS authentication, reloaded when the file changes" env:"CREDHUB_CLIENT_CERT"`
+	ClientKey         string   `long:"client-key" description:"Private key of the client certificate" env:"CREDHUB_CLIENT_KEY"`
+	Token             string   `long:"token" description:"Access token to authenticate with until it expires, it is never refreshed"`
+	ExecCommand       string   `long:"exec-command" description:"Command which prints an access token, run whenever a new token is needed"`
+	ExecArgs          []string `long:"exec-arg" description:"Argument for the exec command, may be given multiple times"`
+	Browser           bool     `long:"browser" description:"Log in through a browser with the authorization code grant"`
+	Device            bool     `long:"device" description:"Log in from another device with the device authorization grant"`
+	OIDC              bool     `long:"oidc" description:"Log in with the OpenID Connect provider at the auth server URL instead of UAA"`
 	ConfigCommand
 }
 
@@ -67,7 +79,26 @@ func (c *LoginCommand) Execute([]string) error {
 	if err != nil {
 		return err
 	}
-	credhubClient, err := credhub.New(c.config.ApiURL, credhub.CaCerts(c.config.CaCerts...), credhub.SkipTLSValidation(c.config.InsecureSkipVerify), credhub.SetHttpTimeout(c.config.HttpTimeout))
+
+	if c.ClientCert != "" || c.ClientKey != "" {
+		return c.loginWithClientCertificate()
+	}
+
+	if c.ExecCommand != "" {
+		return c.loginWithExecCredential()
+	}
+
+	if c.Token != "" {
+		return c.loginWithToken()
+	}
+
+	if c.OIDC {
+		c.config.AuthProvider = config.AuthProviderOIDC
+	} else if c.ServerUrl != "" {
+		c.config.AuthProvider = ""
+	}
+
+	credhubClient, err := credhub.New(c.config.ApiURL, credhub.CaCerts(c.config.CaCerts...), credhub.SkipTLSValidation(c.config.InsecureSkipVerify), credhub.SetHttpTimeout(c.config.HttpTimeout), credhub.Logger(util.DebugLogger()))
 	if err != nil {
 		return err
 	}
@@ -76,16 +107,22 @@ func (c *LoginCommand) Execute([]string) error {
 		AuthURL: c.config.AuthURL,
 		Client:  credhubClient.Client(),
 	}
+	oauthClient := c.config.OAuthClient(credhubClient.Client())
 
-	if c.ClientName != "" || c.ClientSecret != "" {
-		accessToken, err = uaaClient.ClientCredentialGrant(c.ClientName, c.ClientSecret)
-	} else {
+	switch {
+	case c.Browser:
+		accessToken, refreshToken, err = browserLogin(oauthClient)
+	case c.Device:
+		accessToken, refreshToken, err = deviceLogin(&uaaClient)
+	case c.ClientName != "" || c.ClientSecret != "":
+		accessToken, err = oauthClient.ClientCredentialGrant(c.ClientName, c.ClientSecret)
+	default:
 		err = promptForMissingCredentials(c, &uaaClient)
 		if err == nil {
 			if c.SSOPasscode != "" {
 				accessToken, refreshToken, err = uaaClient.PasscodeGrant(config.AuthClient, config.AuthPassword, c.SSOPasscode)
 			} else {
-				accessToken, refreshToken, err = uaaClient.PasswordGrant(config.AuthClient, config.AuthPassword, c.Username, c.Password)
+				accessToken, refreshToken, err = oauthClient.PasswordGrant(config.AuthClient, config.AuthPassword, c.Username, c.Password)
 			}
 		}
 	}
@@ -104,13 +141,19 @@ func (c *LoginCommand) Execute([]string) error {
 	}
 
 	c.config.RefreshToken = refreshToken
+	c.config.ClientCertPath = ""
+	c.config.ClientKeyPath = ""
+	c.config.ExecCommand = nil
+	c.config.AccessTokenExpiry = nil
+	c.config.StaticToken = false
 
 	credhubClient, err = credhub.New(c.config.ApiURL,
 		credhub.CaCerts(c.config.CaCerts...),
 		credhub.SkipTLSValidation(c.config.InsecureSkipVerify),
 		credhub.AuthURL(c.config.AuthURL),
-		credhub.Auth(auth.Uaa(c.ClientName, c.ClientSecret, "", "", c.config.AccessToken, "", true)),
+		credhub.Auth(c.config.OAuthBuilder(c.ClientName, c.ClientSecret, true)),
 		credhub.SetHttpTimeout(c.config.HttpTimeout),
+		credhub.Logger(util.DebugLogger()),
 	)
 
 	if err != nil {
@@ -152,8 +195,228 @@ through command line arguments.
 	return nil
 }
 
+// loginWithClientCertificate replaces any UAA tokens with the client certificate, which authenticates every following request
+func (c *LoginCommand) loginWithClientCertificate() error {
+	certPath, keyPath, err := clientCertificatePaths(c.ClientCert, c.ClientKey)
+	if err != nil {
+		return err
+	}
+
+	credhubClient, err := credhub.New(c.config.ApiURL,
+		credhub.CaCerts(c.config.CaCerts...),
+		credhub.SkipTLSValidation(c.config.InsecureSkipVerify),
+		credhub.ClientCert(certPath, keyPath),
+		credhub.Auth(auth.MutualTLS),
+		credhub.SetHttpTimeout(c.config.HttpTimeout),
+		credhub.Logger(util.DebugLogger()),
+	)
+	if err != nil {
+		return err
+	}
+
+	version, err := credhubClient.ServerVersion()
+	if err != nil {
+		return errors.NewNetworkError(err)
+	}
+
+	RevokeTokenIfNecessary(c.config)
+	c.config.AccessToken = ""
+	c.config.RefreshToken = ""
+	c.config.ClientCertPath = certPath
+	c.config.ClientKeyPath = keyPath
+	c.config.ExecCommand = nil
+	c.config.AccessTokenExpiry = nil
+	c.config.StaticToken = false
+	c.config.ServerVersion = version.String()
+
+	if err := config.WriteConfig(c.config); err != nil {
+		return err
+	}
+
+	if c.ServerUrl != "" {
+		PrintWarnings(c.config.ApiURL, c.SkipTlsValidation)
+		fmt.Println("Setting the target url:", c.config.ApiURL)
+	}
+
+	fmt.Println("Login Successful")
+
+	return nil
+}
+
+// loginWithExecCredential replaces any UAA tokens with the exec credential plugin, which provides the tokens of every following request
+func (c *LoginCommand) loginWithExecCredential() error {
+	execCommand := c.ExecCommand
+	if strings.ContainsRune(execCommand, filepath.Separator) {
+		// keep relative paths working from any working directory, commands on the PATH are saved as given
+		absPath, err := filepath.Abs(execCommand)
+		if err != nil {
+			return err
+		}
+		execCommand = absPath
+	}
+	command := append([]string{execCommand}, c.ExecArgs...)
+
+	credhubClient, err := credhub.New(c.config.ApiURL,
+		credhub.CaCerts(c.config.CaCerts...),
+		credhub.SkipTLSValidation(c.config.InsecureSkipVerify),
+		credhub.Auth(auth.Exec(command[0], command[1:]...)),
+		credhub.SetHttpTimeout(c.config.HttpTimeout),
+		credhub.Logger(util.DebugLogger()),
+	)
+	if err != nil {
+		return err
+	}
+
+	// make sure the plugin works before saving it, and save its token so that the next command does not run it again
+	var accessToken string
+	var accessTokenExpiry *time.Time
+	exec := credhubClient.Auth.(*auth.ExecStrategy)
+	exec.OnToken = func(token string, expiry time.Time) {
+		accessToken = token
+		if !expiry.IsZero() {
+			accessTokenExpiry = &expiry
+		}
+	}
+	if _, err := exec.Token(context.Background()); err != nil {
+		return err
+	}
+
+	version, err := credhubClient.ServerVersion()
+	if err != nil {
+		return errors.NewNetworkError(err)
+	}
+
+	RevokeTokenIfNecessary(c.config)
+	c.config.AccessToken = accessToken
+	c.config.RefreshToken = ""
+	c.config.ClientCertPath = ""
+	c.config.ClientKeyPath = ""
+	c.config.ExecCommand = command
+	c.config.StaticToken = false
+	c.config.AccessTokenExpiry = accessTokenExpiry
+	c.config.ServerVersion = version.String()
+
+	if err := config.WriteConfig(c.config); err != nil {
+		return err
+	}
+
+	if c.ServerUrl != "" {
+		PrintWarnings(c.config.ApiURL, c.SkipTlsValidation)
+		fmt.Println("Setting the target url:", c.config.ApiURL)
+	}
+
+	fmt.Println("Login Successful")
+
+	return nil
+}
+
+// loginWithToken saves an access token issued by someone else, which authenticates every following request until it expires
+func (c *LoginCommand) loginWithToken() error {
+	credhubClient, err := credhub.New(c.config.ApiURL,
+		credhub.CaCerts(c.config.CaCerts...),
+		credhub.SkipTLSValidation(c.config.InsecureSkipVerify),
+		credhub.Auth(auth.BearerToken(c.Token)),
+		credhub.SetHttpTimeout(c.config.HttpTimeout),
+		credhub.Logger(util.DebugLogger()),
+	)
+	if err != nil {
+		return err
+	}
+
+	version, err := credhubClient.ServerVersion()
+	if err != nil {
+		return errors.NewNetworkError(err)
+	}
+
+	RevokeTokenIfNecessary(c.config)
+	c.config.AccessToken = c.Token
+	c.config.RefreshToken = ""
+	c.config.StaticToken = true
+	c.config.ClientCertPath = ""
+	c.config.ClientKeyPath = ""
+	c.config.ExecCommand = nil
+	c.config.AccessTokenExpiry = nil
+	c.config.ServerVersion = version.String()
+
+	if err := config.WriteConfig(c.config); err != nil {
+		return err
+	}
+
+	if c.ServerUrl != "" {
+		PrintWarnings(c.config.ApiURL, c.SkipTlsValidation)
+		fmt.Println("Setting the target url:", c.config.ApiURL)
+	}
+
+	fmt.Println("Login Successful")
+
+	return nil
+}
+
 func validateParameters(cmd *LoginCommand) error {
+	// One-time passcodes are a UAA extension
+	if cmd.OIDC && (cmd.SSO || cmd.SSOPasscode != "") {
+		return errors.NewOIDCSSOError()
+	}
+
+	if cmd.OIDC && cmd.Device {
+		return errors.NewOIDCDeviceError()
+	}
+
+	if len(cmd.ExecArgs) > 0 && cmd.ExecCommand == "" {
+		return errors.NewExecArgsWithoutCommandError()
+	}
+
 	switch {
+	// Intent is client certificate
+	case cmd.ClientCert != "" || cmd.ClientKey != "":
+		// Make sure nothing else is specified
+		if cmd.ClientName != "" || cmd.ClientSecret != "" || cmd.Username != "" || cmd.Password != "" || cmd.SSO || cmd.SSOPasscode != "" || cmd.OIDC || cmd.Browser || cmd.Device || cmd.ExecCommand != "" || cmd.Token != "" {
+			return errors.NewMixedAuthorizationParametersError()
+		}
+
+		// Make sure all required fields are specified
+		if cmd.ClientCert == "" || cmd.ClientKey == "" {
+			return errors.NewClientCertificateParametersError()
+		}
+
+		return nil
+
+	// Intent is exec credential plugin
+	case cmd.ExecCommand != "":
+		// Make sure nothing else is specified
+		if cmd.ClientName != "" || cmd.ClientSecret != "" || cmd.Username != "" || cmd.Password != "" || cmd.SSO || cmd.SSOPasscode != "" || cmd.OIDC || cmd.Browser || cmd.Device || cmd.Token != "" {
+			return errors.NewMixedAuthorizationParametersError()
+		}
+
+		return nil
+
+	// Intent is access token
+	case cmd.Token != "":
+		// Make sure nothing else is specified
+		if cmd.ClientName != "" || cmd.ClientSecret != "" || cmd.Username != "" || cmd.Password != "" || cmd.SSO || cmd.SSOPasscode != "" || cmd.OIDC || cmd.Browser || cmd.Device {
+			return errors.NewMixedAuthorizationParametersError()
+		}
+
+		return nil
+
+	// Intent is browser login
+	case cmd.Browser:
+		// Make sure nothing else is specified
+		if cmd.ClientName != "" || cmd.ClientSecret != "" || cmd.Username != "" || cmd.Password != "" || cmd.SSO || cmd.SSOPasscode != "" || cmd.Device {
+			return errors.NewMixedAuthorizationParametersError()
+		}
+
+		return nil
+
+	// Intent is device login
+	case cmd.Device:
+		// Make sure nothing else is specified
+		if cmd.ClientName != "" || cmd.ClientSecret != "" || cmd.Username != "" || cmd.Password != "" || cmd.SSO || cmd.SSOPasscode != "" {
+			return errors.NewMixedAuthorizationParametersError()
+		}
+
+		return nil
+
 	// Intent is client credentials
 	case cmd.ClientName != "" || cmd.ClientSecret != "":
 		// Make sure nothing else is specified
@@ -230,6 +493,22 @@ func promptForMissingCredentials(cmd *LoginCommand, uaa *uaa.Client) error {
 	return nil
 }
 
+// deviceLogin asks the user to complete the login on another device, and waits until they have
+func deviceLogin(uaaClient *uaa.Client) (string, string, error) {
+	authorization, err := uaaClient.DeviceAuthorization(config.AuthClient, config.AuthPassword)
+	if err != nil {
+		return "", "", err
+	}
+
+	fmt.Printf("To log in, visit %s and enter the code %s\n", authorization.VerificationURI, authorization.UserCode)
+	if authorization.VerificationURIComplete != "" {
+		fmt.Printf("or open %s\n", authorization.VerificationURIComplete)
+	}
+	fmt.Println("Waiting for the login to complete...")
+
+	return uaaClient.DeviceAccessTokenGrant(config.AuthClient, config.AuthPassword, authorization)
+}
+
 func getPasswordMasked() ([]byte, error) {
 	stdin := os.Stdin
 	if term.IsTerminal(int(stdin.Fd())) {
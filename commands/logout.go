@@ -1,11 +1,12 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 
 	"code.cloudfoundry.org/credhub-cli/config"
 	"code.cloudfoundry.org/credhub-cli/credhub"
-	"code.cloudfoundry.org/credhub-cli/credhub/auth/uaa"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth/oidc"
 	"code.cloudfoundry.org/credhub-cli/util"
 )
 
@@ -18,6 +19,9 @@ func (c *LogoutCommand) Execute([]string) error {
 		return err
 	}
 	MarkTokensAsRevokedInConfig(&c.config)
+	c.config.ClientCertPath = ""
+	c.config.ClientKeyPath = ""
+	c.config.ExecCommand = nil
 	if err := config.WriteConfig(c.config); err != nil {
 		return err
 	}
@@ -26,18 +30,21 @@ func (c *LogoutCommand) Execute([]string) error {
 }
 
 func RevokeTokenIfNecessary(cfg config.Config) error {
-	credhubClient, err := credhub.New(cfg.ApiURL, credhub.CaCerts(cfg.CaCerts...), credhub.SkipTLSValidation(cfg.InsecureSkipVerify), credhub.SetHttpTimeout(cfg.HttpTimeout))
+	credhubClient, err := credhub.New(cfg.ApiURL, credhub.CaCerts(cfg.CaCerts...), credhub.SkipTLSValidation(cfg.InsecureSkipVerify), credhub.SetHttpTimeout(cfg.HttpTimeout), credhub.Logger(util.DebugLogger()))
 	if err != nil {
 		return err
 	}
 
-	uaaClient := uaa.Client{
-		AuthURL: cfg.AuthURL,
-		Client:  credhubClient.Client(),
-	}
+	oauthClient := cfg.OAuthClient(credhubClient.Client())
 
-	if util.TokenIsPresent(cfg.AccessToken) {
-		return uaaClient.RevokeToken(cfg.AccessToken)
+	// tokens given to login --token or printed by an exec credential plugin are owned by whoever issued them
+	if util.TokenIsPresent(cfg.AccessToken) && !cfg.StaticToken && !cfg.UsesExecCredential() {
+		err := oauthClient.RevokeToken(cfg.AccessToken)
+		if errors.Is(err, oidc.ErrRevocationNotSupported) {
+			// the token stays valid until it expires, but is forgotten by the CLI
+			return nil
+		}
+		return err
 	}
 
 	return nil
@@ -46,4 +53,6 @@ func RevokeTokenIfNecessary(cfg config.Config) error {
 func MarkTokensAsRevokedInConfig(cfg *config.Config) {
 	cfg.AccessToken = "revoked"
 	cfg.RefreshToken = "revoked"
+	cfg.StaticToken = false
+	cfg.AccessTokenExpiry = nil
 }
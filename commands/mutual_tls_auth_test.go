@@ -0,0 +1,166 @@
+package commands_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"path/filepath"
+
+	"code.cloudfoundry.org/credhub-cli/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Mutual TLS authentication", func() {
+	var (
+		clientCertPath string
+		clientKeyPath  string
+		peerCerts      [][]*x509.Certificate
+		authHeaders    []string
+	)
+
+	BeforeEach(func() {
+		var err error
+		clientCertPath, err = filepath.Abs("../test/auth-tls-cert.pem")
+		Expect(err).NotTo(HaveOccurred())
+		clientKeyPath, err = filepath.Abs("../test/auth-tls-key.pem")
+		Expect(err).NotTo(HaveOccurred())
+
+		peerCerts = nil
+		authHeaders = nil
+
+		// CredHub asks for client certificates without requiring them
+		server.HTTPTestServer.TLS.ClientAuth = tls.RequestClientCert
+
+		server.RouteToHandler("GET", "/info",
+			RespondWith(http.StatusOK, `{
+				"app":{"name":"CredHub","version":"2.9.0"},
+				"auth-server":{"url":"`+authServer.URL()+`"}
+				}`),
+		)
+		server.RouteToHandler("GET", "/api/v1/data", func(w http.ResponseWriter, r *http.Request) {
+			peerCerts = append(peerCerts, r.TLS.PeerCertificates)
+			authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+			w.Write([]byte(`{"credentials": []}`))
+		})
+		authServer.RouteToHandler("GET", "/info", RespondWith(http.StatusOK, ""))
+	})
+
+	Describe("credhub login", func() {
+		It("persists the client certificate and uses it instead of a token", func() {
+			session := runCommand("login", "--client-cert", "../test/auth-tls-cert.pem", "--client-key", "../test/auth-tls-key.pem")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say("Login Successful"))
+			Expect(authServer.ReceivedRequests()).To(BeEmpty())
+
+			cfg := config.ReadConfig()
+			Expect(cfg.ClientCertPath).To(Equal(clientCertPath))
+			Expect(cfg.ClientKeyPath).To(Equal(clientKeyPath))
+			Expect(cfg.AccessToken).To(BeEmpty())
+			Expect(cfg.ServerVersion).To(Equal("2.9.0"))
+
+			session = runCommand("find")
+
+			Eventually(session).Should(Exit(0))
+			Expect(authHeaders).To(Equal([]string{""}))
+			Expect(peerCerts).To(HaveLen(1))
+			Expect(peerCerts[0]).To(HaveLen(1))
+			Expect(peerCerts[0][0].Subject.CommonName).To(Equal("example.com"))
+		})
+
+		It("requires both the certificate and the key", func() {
+			session := runCommand("login", "--client-cert", "../test/auth-tls-cert.pem")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("Both client certificate and client key must be provided to authenticate with a client certificate. Please update and retry your request."))
+		})
+
+		It("cannot be combined with other login methods", func() {
+			session := runCommand("login", "--client-cert", "../test/auth-tls-cert.pem", "--client-key", "../test/auth-tls-key.pem", "-u", "some-user")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("Client, password, SSO and/or SSO passcode credentials may not be combined."))
+		})
+
+		It("fails when the certificate cannot be loaded", func() {
+			session := runCommand("login", "--client-cert", "../test/auth-tls-key.pem", "--client-key", "../test/auth-tls-cert.pem")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("The client certificate could not be loaded: "))
+			Expect(config.ReadConfig().ClientCertPath).To(BeEmpty())
+		})
+	})
+
+	Describe("credhub logout", func() {
+		It("forgets the client certificate", func() {
+			Eventually(runCommand("login", "--client-cert", "../test/auth-tls-cert.pem", "--client-key", "../test/auth-tls-key.pem")).Should(Exit(0))
+
+			session := runCommand("logout")
+
+			Eventually(session).Should(Exit(0))
+			cfg := config.ReadConfig()
+			Expect(cfg.ClientCertPath).To(BeEmpty())
+			Expect(cfg.ClientKeyPath).To(BeEmpty())
+
+			session = runCommand("find")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("You are not currently authenticated. Please log in to continue."))
+		})
+	})
+
+	Describe("credhub api", func() {
+		It("persists the client certificate", func() {
+			session := runCommand("api", server.URL(), "--ca-cert", "../test/server-tls-ca.pem", "--ca-cert", "../test/auth-tls-ca.pem", "--client-cert", "../test/auth-tls-cert.pem", "--client-key", "../test/auth-tls-key.pem")
+
+			Eventually(session).Should(Exit(0))
+			cfg := config.ReadConfig()
+			Expect(cfg.ClientCertPath).To(Equal(clientCertPath))
+			Expect(cfg.ClientKeyPath).To(Equal(clientKeyPath))
+
+			session = runCommand("find")
+
+			Eventually(session).Should(Exit(0))
+			Expect(peerCerts).To(HaveLen(1))
+			Expect(peerCerts[0]).To(HaveLen(1))
+		})
+	})
+
+	Describe("with the client certificate in the environment", func() {
+		It("authenticates without logging in", func() {
+			config.WriteConfig(config.Config{})
+
+			session := runCommandWithEnv([]string{
+				"CREDHUB_SERVER=" + server.URL(),
+				"CREDHUB_CA_CERT=../test/server-and-auth-stacked-cert.pem",
+				"CREDHUB_CLIENT_CERT=" + clientCertPath,
+				"CREDHUB_CLIENT_KEY=" + clientKeyPath,
+			}, "find")
+
+			Eventually(session).Should(Exit(0))
+			Expect(authHeaders).To(Equal([]string{""}))
+			Expect(peerCerts).To(HaveLen(1))
+			Expect(peerCerts[0]).To(HaveLen(1))
+		})
+
+		It("keeps the saved client certificate login", func() {
+			Eventually(runCommand("login", "--client-cert", "../test/auth-tls-cert.pem", "--client-key", "../test/auth-tls-key.pem")).Should(Exit(0))
+
+			session := runCommandWithEnv([]string{
+				"CREDHUB_SERVER=" + server.URL(),
+				"CREDHUB_CA_CERT=../test/server-and-auth-stacked-cert.pem",
+				"CREDHUB_CLIENT=some-client",
+				"CREDHUB_SECRET=some-secret",
+			}, "--version")
+
+			Eventually(session).Should(Exit(0))
+			cfg := config.ReadConfig()
+			Expect(cfg.ClientCertPath).To(Equal(clientCertPath))
+			Expect(cfg.ClientKeyPath).To(Equal(clientKeyPath))
+		})
+	})
+})
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/errors"
+)
+
+type RotateCACommand struct {
+	CredentialIdentifier string `required:"yes" short:"n" long:"name" description:"Name of the certificate authority to rotate"`
+	Step                 string `required:"yes" long:"step" choice:"new" choice:"swap" choice:"clean" description:"Step of the rotation to run: new, swap or clean"`
+	ClientCommand
+}
+
+func (c *RotateCACommand) Execute([]string) error {
+	metadata, err := c.client.GetCertificateMetadataByName(c.CredentialIdentifier)
+	if err != nil {
+		return err
+	}
+
+	switch c.Step {
+	case "new":
+		return c.newVersion(metadata)
+	case "swap":
+		return c.swap(metadata)
+	default:
+		return c.clean(metadata)
+	}
+}
+
+// newVersion generates a transitional version of the CA, which is trusted but does not sign certificates yet
+func (c *RotateCACommand) newVersion(metadata credentials.CertificateMetadata) error {
+	if len(metadata.Versions) == 0 || !metadata.Versions[0].CertificateAuthority {
+		return errors.NewNotACertificateAuthorityError(metadata.Name)
+	}
+	if transitionalVersion(metadata) >= 0 {
+		return errors.NewRotationInProgressError(metadata.Name)
+	}
+
+	if _, err := c.client.RegenerateCertificate(metadata.Id, true); err != nil {
+		return err
+	}
+
+	fmt.Printf("A new transitional version of '%s' was generated.\n", metadata.Name)
+	fmt.Printf("Redeploy so that clients trust it, then run 'credhub rotate-ca -n %s --step swap'.\n", metadata.Name)
+	return nil
+}
+
+// swap makes the new version of the CA sign certificates, keeps the previous version as transitional and
+// regenerates the certificates signed by the CA.
+//
+// When the versions were already swapped, only the certificates are regenerated, so that a swap whose
+// regeneration failed can be run again.
+func (c *RotateCACommand) swap(metadata credentials.CertificateMetadata) error {
+	transitional := transitionalVersion(metadata)
+	if transitional < 0 || len(metadata.Versions) < 2 {
+		return errors.NewRotationStepOutOfOrderError(metadata.Name, "swap", "new")
+	}
+
+	if transitional == 0 {
+		previous := metadata.Versions[1].Id
+
+		if _, err := c.client.UpdateTransitionalVersion(metadata.Id, previous); err != nil {
+			return err
+		}
+	}
+
+	results, err := c.client.BulkRegenerate(metadata.Name)
+	if err != nil {
+		return errors.NewRotationRegenerateError(metadata.Name, err)
+	}
+
+	fmt.Printf("The new version of '%s' now signs certificates and the previous version is transitional.\n", metadata.Name)
+	for _, name := range results.Certificates {
+		fmt.Printf("Regenerated '%s'.\n", name)
+	}
+	fmt.Printf("Redeploy so that the regenerated certificates are used, then run 'credhub rotate-ca -n %s --step clean'.\n", metadata.Name)
+	return nil
+}
+
+// clean removes the transitional flag from the previous version of the CA, so that it is no longer trusted
+func (c *RotateCACommand) clean(metadata credentials.CertificateMetadata) error {
+	switch transitionalVersion(metadata) {
+	case -1:
+		return errors.NewRotationStepOutOfOrderError(metadata.Name, "clean", "new")
+	case 0:
+		return errors.NewRotationStepOutOfOrderError(metadata.Name, "clean", "swap")
+	}
+
+	if _, err := c.client.UpdateTransitionalVersion(metadata.Id, ""); err != nil {
+		return err
+	}
+
+	fmt.Printf("The previous version of '%s' is no longer transitional and the rotation is complete.\n", metadata.Name)
+	fmt.Println("Redeploy so that clients stop trusting it.")
+	return nil
+}
+
+// transitionalVersion returns the index of the transitional version among the active versions of the
+// certificate, newest first, or -1 when it has none
+func transitionalVersion(metadata credentials.CertificateMetadata) int {
+	for i, version := range metadata.Versions {
+		if version.Transitional {
+			return i
+		}
+	}
+	return -1
+}
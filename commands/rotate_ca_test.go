@@ -0,0 +1,210 @@
+package commands_test
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+const (
+	CA_METADATA_JSON = `{"certificates": [{
+	"id": "ca-id",
+	"name": "/ca",
+	"signed_by": "/ca",
+	"signs": ["/leaf"],
+	"versions": [
+		{"id": "ca-version", "expiry_date": "2030-01-01T00:00:00Z", "transitional": false, "certificate_authority": true, "self_signed": true}
+	]
+}]}`
+	CA_NEW_METADATA_JSON = `{"certificates": [{
+	"id": "ca-id",
+	"name": "/ca",
+	"signed_by": "/ca",
+	"signs": ["/leaf"],
+	"versions": [
+		{"id": "ca-new-version", "expiry_date": "2031-01-01T00:00:00Z", "transitional": true, "certificate_authority": true, "self_signed": true},
+		{"id": "ca-version", "expiry_date": "2030-01-01T00:00:00Z", "transitional": false, "certificate_authority": true, "self_signed": true}
+	]
+}]}`
+	CA_SWAPPED_METADATA_JSON = `{"certificates": [{
+	"id": "ca-id",
+	"name": "/ca",
+	"signed_by": "/ca",
+	"signs": ["/leaf"],
+	"versions": [
+		{"id": "ca-new-version", "expiry_date": "2031-01-01T00:00:00Z", "transitional": false, "certificate_authority": true, "self_signed": true},
+		{"id": "ca-version", "expiry_date": "2030-01-01T00:00:00Z", "transitional": true, "certificate_authority": true, "self_signed": true}
+	]
+}]}`
+	CA_VERSION_JSON = `{"id": "ca-new-version", "name": "/ca", "type": "certificate", "value": {"certificate": "some-certificate"}}`
+)
+
+var _ = Describe("Rotate-CA", func() {
+	BeforeEach(func() {
+		login()
+	})
+
+	ItRequiresAuthentication("rotate-ca", "-n", "/ca", "--step", "new")
+	ItRequiresAnAPIToBeSet("rotate-ca", "-n", "/ca", "--step", "new")
+
+	respondWithMetadata := func(metadata string) {
+		server.RouteToHandler("GET", "/api/v1/certificates/",
+			CombineHandlers(
+				VerifyRequest("GET", "/api/v1/certificates/", "name=%2Fca"),
+				RespondWith(http.StatusOK, metadata),
+			),
+		)
+	}
+
+	It("requires a valid step", func() {
+		session := runCommand("rotate-ca", "-n", "/ca", "--step", "unknown")
+
+		Eventually(session).Should(Exit(1))
+		Expect(session.Err).To(Say("new"))
+	})
+
+	Describe("the new step", func() {
+		It("generates a transitional version of the CA", func() {
+			respondWithMetadata(CA_METADATA_JSON)
+			server.RouteToHandler("POST", "/api/v1/certificates/ca-id/regenerate",
+				CombineHandlers(
+					VerifyJSON(`{"set_as_transitional": true}`),
+					RespondWith(http.StatusOK, CA_VERSION_JSON),
+				),
+			)
+
+			session := runCommand("rotate-ca", "-n", "/ca", "--step", "new")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say("A new transitional version of '/ca' was generated."))
+			Expect(session.Out).To(Say("credhub rotate-ca -n /ca --step swap"))
+		})
+
+		It("fails when a rotation is already in progress", func() {
+			respondWithMetadata(CA_NEW_METADATA_JSON)
+
+			session := runCommand("rotate-ca", "-n", "/ca", "--step", "new")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("A rotation of '/ca' is already in progress."))
+		})
+
+		It("fails when the certificate is not a CA", func() {
+			respondWithMetadata(`{"certificates": [{"id": "leaf-id", "name": "/ca", "signed_by": "/other-ca", "signs": [], "versions": [
+	{"id": "leaf-version", "expiry_date": "2030-01-01T00:00:00Z", "transitional": false, "certificate_authority": false, "self_signed": false}
+]}]}`)
+
+			session := runCommand("rotate-ca", "-n", "/ca", "--step", "new")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("The certificate '/ca' is not a certificate authority."))
+		})
+	})
+
+	Describe("the swap step", func() {
+		It("makes the previous version transitional and regenerates the signed certificates", func() {
+			respondWithMetadata(CA_NEW_METADATA_JSON)
+			server.RouteToHandler("PUT", "/api/v1/certificates/ca-id/update_transitional_version",
+				CombineHandlers(
+					VerifyJSON(`{"version": "ca-version"}`),
+					RespondWith(http.StatusOK, `[]`),
+				),
+			)
+			server.RouteToHandler("POST", "/api/v1/bulk-regenerate",
+				CombineHandlers(
+					VerifyJSON(`{"signed_by": "/ca"}`),
+					RespondWith(http.StatusOK, `{"regenerated_credentials": ["/leaf"]}`),
+				),
+			)
+
+			session := runCommand("rotate-ca", "-n", "/ca", "--step", "swap")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say("The new version of '/ca' now signs certificates"))
+			Expect(session.Out).To(Say("Regenerated '/leaf'."))
+			Expect(session.Out).To(Say("credhub rotate-ca -n /ca --step clean"))
+		})
+
+		It("fails when there is no new version", func() {
+			respondWithMetadata(CA_METADATA_JSON)
+
+			session := runCommand("rotate-ca", "-n", "/ca", "--step", "swap")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("The 'swap' step of the rotation of '/ca' cannot be run yet. Please run the 'new' step first."))
+		})
+
+		It("asks to run the step again when the signed certificates could not be regenerated", func() {
+			respondWithMetadata(CA_NEW_METADATA_JSON)
+			server.RouteToHandler("PUT", "/api/v1/certificates/ca-id/update_transitional_version",
+				RespondWith(http.StatusOK, `[]`),
+			)
+			server.RouteToHandler("POST", "/api/v1/bulk-regenerate",
+				RespondWith(http.StatusInternalServerError, `{"error": "some error"}`),
+			)
+
+			session := runCommand("rotate-ca", "-n", "/ca", "--step", "swap")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("The new version of '/ca' signs certificates, but the certificates it signs could not be regenerated: some error. Please run the 'swap' step again to regenerate them."))
+		})
+
+		It("only regenerates the signed certificates when the versions were already swapped", func() {
+			respondWithMetadata(CA_SWAPPED_METADATA_JSON)
+			server.RouteToHandler("POST", "/api/v1/bulk-regenerate",
+				CombineHandlers(
+					VerifyJSON(`{"signed_by": "/ca"}`),
+					RespondWith(http.StatusOK, `{"regenerated_credentials": ["/leaf"]}`),
+				),
+			)
+
+			session := runCommand("rotate-ca", "-n", "/ca", "--step", "swap")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say("Regenerated '/leaf'."))
+			Expect(session.Out).To(Say("credhub rotate-ca -n /ca --step clean"))
+			for _, request := range server.ReceivedRequests() {
+				Expect(request.URL.Path).NotTo(HaveSuffix("update_transitional_version"))
+			}
+		})
+	})
+
+	Describe("the clean step", func() {
+		It("removes the transitional flag", func() {
+			respondWithMetadata(CA_SWAPPED_METADATA_JSON)
+			server.RouteToHandler("PUT", "/api/v1/certificates/ca-id/update_transitional_version",
+				CombineHandlers(
+					VerifyJSON(`{"version": null}`),
+					RespondWith(http.StatusOK, `[]`),
+				),
+			)
+
+			session := runCommand("rotate-ca", "-n", "/ca", "--step", "clean")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say("the rotation is complete"))
+		})
+
+		It("fails before the versions were swapped", func() {
+			respondWithMetadata(CA_NEW_METADATA_JSON)
+
+			session := runCommand("rotate-ca", "-n", "/ca", "--step", "clean")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("Please run the 'swap' step first."))
+		})
+
+		It("fails when the CA is not being rotated", func() {
+			respondWithMetadata(CA_METADATA_JSON)
+
+			session := runCommand("rotate-ca", "-n", "/ca", "--step", "clean")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("Please run the 'new' step first."))
+		})
+	})
+})
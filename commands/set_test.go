@@ -71,6 +71,33 @@ var _ = Describe("Set", func() {
 			Expect(string(session.Out.Contents())).NotTo(ContainSubstring("metadata:"))
 		})
 
+		Describe("when the server is unavailable", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					CombineHandlers(
+						VerifyRequest("PUT", "/api/v1/data"),
+						RespondWith(http.StatusServiceUnavailable, `{"error":"service unavailable"}`),
+					),
+				)
+			})
+
+			It("does not retry the request with --retries alone", func() {
+				session := runCommand("set", "-n", "my-value", "-v", "potatoes", "-t", "value", "--retries", "1")
+
+				Eventually(session).Should(Exit(1))
+				Expect(session.Err).To(Say("service unavailable"))
+			})
+
+			It("retries the request with --retry-put", func() {
+				setupSetServer("my-value", "value", `"potatoes"`)
+
+				session := runCommand("set", "-n", "my-value", "-v", "potatoes", "-t", "value", "--retries", "1", "--retry-put")
+
+				Eventually(session).Should(Exit(0))
+				Expect(session.Out).To(Say("name: my-value"))
+			})
+		})
+
 		It("puts a secret with metadata", func() {
 			setupSetServerWithMetadata("my-value", "value", `"potatoes"`, `{"some":{"example":"metadata"}, "array":["metadata"]}`)
 
@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/credhub-cli/config"
+	"code.cloudfoundry.org/credhub-cli/errors"
+)
+
+type TargetCommand struct {
+	Add    TargetAddCommand    `command:"add" description:"Save a CredHub server as a named target" long-description:"Save a CredHub server as a named target. Log in to it with 'credhub --target NAME login', or make it the current target with 'credhub target use NAME'."`
+	Use    TargetUseCommand    `command:"use" description:"Send commands to a named target" long-description:"Make a named target the current target, which commands are sent to unless --target or CREDHUB_TARGET select another"`
+	List   TargetListCommand   `command:"list" description:"List the named targets" long-description:"List the named targets and their API URLs"`
+	Delete TargetDeleteCommand `command:"delete" description:"Delete a named target" long-description:"Delete a named target and discard its authenticated session"`
+}
+
+type TargetNameArgs struct {
+	Name string `positional-arg-name:"NAME" description:"Name of the target" required:"yes"`
+}
+
+type TargetAddCommand struct {
+	Args              TargetNameArgs `positional-args:"yes"`
+	ServerFlagUrl     string         `short:"s" long:"server" required:"yes" description:"URI of API server to target"`
+	CaCerts           []string       `long:"ca-cert" description:"Trusted CA for API and UAA TLS connections. Multiple flags may be provided."`
+	SkipTlsValidation bool           `long:"skip-tls-validation" description:"Skip certificate validation of the API endpoint. Not recommended!"`
+	Use               bool           `long:"use" description:"Make the new target the current target"`
+	ConfigCommand
+}
+
+func (c *TargetAddCommand) Execute([]string) error {
+	if _, err := config.ReadTarget(c.Args.Name); err == nil {
+		return errors.NewTargetExistsError(c.Args.Name)
+	}
+
+	api := ApiCommand{
+		ServerFlagUrl:     c.ServerFlagUrl,
+		CaCerts:           c.CaCerts,
+		SkipTlsValidation: c.SkipTlsValidation,
+	}
+	api.SetConfig(config.Config{
+		ConfigWithoutSecrets: config.ConfigWithoutSecrets{HttpTimeout: c.config.HttpTimeout},
+		Target:               c.Args.Name,
+	})
+	if err := api.Execute(nil); err != nil {
+		return err
+	}
+
+	if c.Use {
+		if err := config.UseTarget(c.Args.Name); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Added target '%s'\n", c.Args.Name)
+	return nil
+}
+
+type TargetUseCommand struct {
+	Args TargetNameArgs `positional-args:"yes"`
+}
+
+func (c *TargetUseCommand) Execute([]string) error {
+	if err := config.UseTarget(c.Args.Name); err != nil {
+		return err
+	}
+
+	fmt.Printf("Using target '%s'\n", c.Args.Name)
+	return nil
+}
+
+type TargetListCommand struct {
+	OutputJSON bool `short:"j" long:"output-json" description:"Return response in JSON format"`
+}
+
+func (c *TargetListCommand) Execute([]string) error {
+	targets, err := config.ListTargets()
+	if err != nil {
+		return err
+	}
+
+	formatOutput(c.OutputJSON, map[string]interface{}{"targets": targets})
+	return nil
+}
+
+type TargetDeleteCommand struct {
+	Args TargetNameArgs `positional-args:"yes"`
+}
+
+func (c *TargetDeleteCommand) Execute([]string) error {
+	cfg, err := config.ReadTarget(c.Args.Name)
+	if err != nil {
+		return err
+	}
+
+	// the target is deleted even when its auth server can no longer be reached
+	RevokeTokenIfNecessary(cfg)
+
+	selected, err := config.DeleteTarget(c.Args.Name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Deleted target '%s'\n", c.Args.Name)
+	if selected == "" {
+		fmt.Println("No target is selected. Select one with 'credhub target use NAME'.")
+	} else {
+		fmt.Printf("Using target '%s'\n", selected)
+	}
+	return nil
+}
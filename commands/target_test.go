@@ -0,0 +1,154 @@
+package commands_test
+
+import (
+	"net/http"
+	"os"
+
+	"code.cloudfoundry.org/credhub-cli/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Target", func() {
+	var prodServer *Server
+
+	BeforeEach(func() {
+		prodServer = NewTlsServer("../test/server-tls-cert.pem", "../test/server-tls-key.pem")
+		DeferCleanup(prodServer.Close)
+
+		SetupServers(server, authServer)
+		SetupServers(prodServer, authServer)
+	})
+
+	addProd := func(args ...string) *Session {
+		return runCommand(append([]string{"target", "add", "prod", "-s", prodServer.URL(), "--ca-cert", "../test/server-tls-ca.pem", "--ca-cert", "../test/auth-tls-ca.pem"}, args...)...)
+	}
+
+	It("adds a target without switching to it", func() {
+		session := addProd()
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say("Added target 'prod'"))
+
+		session = runCommand("api")
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say(server.URL()))
+
+		session = runCommand("target", "list")
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say(`targets:
+    - name: default
+      api_url: ` + server.URL() + `
+      current: true
+    - name: prod
+      api_url: ` + prodServer.URL() + `
+      current: false`))
+	})
+
+	It("does not make the first target added the current target", func() {
+		Expect(os.Remove(config.ConfigPath())).To(Succeed())
+
+		Eventually(addProd()).Should(Exit(0))
+
+		Expect(config.ReadConfig().Target).To(Equal(config.DefaultTarget))
+		Expect(config.ListTargets()).To(Equal([]config.Target{
+			{Name: "prod", ApiURL: prodServer.URL()},
+		}))
+	})
+
+	It("keeps a session for each target", func() {
+		Eventually(addProd("--use")).Should(Exit(0))
+		Expect(config.ReadConfig().Target).To(Equal("prod"))
+
+		authServer.AppendHandlers(
+			RespondWith(http.StatusOK, `{"access_token":"prod-access-token","refresh_token":"prod-refresh-token","token_type":"password","expires_in":123456789}`),
+		)
+		session := runCommand("login", "-u", "test-username", "-p", "test-password")
+		Eventually(session).Should(Exit(0))
+
+		prodServer.RouteToHandler("GET", "/api/v1/data",
+			CombineHandlers(
+				VerifyHeaderKV("Authorization", "Bearer prod-access-token"),
+				RespondWith(http.StatusOK, `{"credentials": []}`),
+			),
+		)
+
+		session = runCommand("target", "use", "default")
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say("Using target 'default'"))
+
+		session = runCommand("find")
+		Eventually(session).Should(Exit(1))
+		Expect(session.Err).To(Say("You are not currently authenticated."))
+
+		session = runCommand("--target", "prod", "find")
+		Eventually(session).Should(Exit(0))
+
+		session = runCommandWithEnv([]string{"CREDHUB_TARGET=prod"}, "find")
+		Eventually(session).Should(Exit(0))
+	})
+
+	It("deletes a target and revokes its session", func() {
+		Eventually(addProd()).Should(Exit(0))
+
+		cfg, err := config.ReadTarget("prod")
+		Expect(err).NotTo(HaveOccurred())
+		cfg.AccessToken = "prod-access-token"
+		Expect(config.WriteConfig(cfg)).To(Succeed())
+
+		authServer.AppendHandlers(
+			VerifyRequest("DELETE", "/oauth/token/revoke/prod-access-token"),
+		)
+
+		session := runCommand("target", "delete", "prod")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say("Deleted target 'prod'"))
+		Expect(session.Out).To(Say("Using target 'default'"))
+		Expect(authServer.ReceivedRequests()).To(HaveLen(1))
+
+		_, err = config.ReadTarget("prod")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("reports the target selected after the current target is deleted", func() {
+		Eventually(addProd("--use")).Should(Exit(0))
+
+		session := runCommand("target", "delete", "prod")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say("Deleted target 'prod'"))
+		Expect(session.Out).To(Say("Using target 'default'"))
+		Expect(config.ReadConfig().ApiURL).To(Equal(server.URL()))
+	})
+
+	It("reports that no target is selected when the default target does not exist", func() {
+		Expect(os.Remove(config.ConfigPath())).To(Succeed())
+		Eventually(addProd("--use")).Should(Exit(0))
+
+		session := runCommand("target", "delete", "prod")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say("Deleted target 'prod'"))
+		Expect(session.Out).To(Say("No target is selected. Select one with 'credhub target use NAME'."))
+	})
+
+	It("refuses to add a target which already exists", func() {
+		Eventually(addProd()).Should(Exit(0))
+
+		session := addProd()
+
+		Eventually(session).Should(Exit(1))
+		Expect(session.Err).To(Say("The target 'prod' already exists."))
+	})
+
+	It("refuses to use a target which does not exist", func() {
+		session := runCommand("target", "use", "staging")
+
+		Eventually(session).Should(Exit(1))
+		Expect(session.Err).To(Say("The target 'staging' does not exist."))
+	})
+})
@@ -1,11 +1,13 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"os"
 
 	"code.cloudfoundry.org/credhub-cli/config"
 	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+	"code.cloudfoundry.org/credhub-cli/errors"
 	"code.cloudfoundry.org/credhub-cli/util"
 )
 
@@ -13,12 +15,29 @@ func init() {
 	CredHub.Token = func() {
 		cfg := config.ReadConfig()
 
-		if util.TokenIsPresent(cfg.AccessToken) {
-			cfg = refreshConfiguration(cfg)
+		if cfg.Token != "" {
+			fmt.Println("Bearer " + cfg.Token)
+		} else if cfg.UsesExecCredential() {
+			accessToken, err := execToken(cfg)
+			if err != nil {
+				fmt.Fprint(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			fmt.Println("Bearer " + accessToken)
+		} else if util.TokenIsPresent(cfg.AccessToken) {
+			cfg, err := refreshConfiguration(cfg)
+			if err != nil {
+				fmt.Fprint(os.Stderr, err.Error())
+				os.Exit(1)
+			}
 			config.WriteConfig(cfg)
 			fmt.Println("Bearer " + cfg.AccessToken)
 		} else if os.Getenv("CREDHUB_CLIENT") != "" && os.Getenv("CREDHUB_SECRET") != "" {
-			cfg = refreshConfiguration(cfg)
+			cfg, err := refreshConfiguration(cfg)
+			if err != nil {
+				fmt.Fprint(os.Stderr, err.Error())
+				os.Exit(1)
+			}
 			fmt.Println("Bearer " + cfg.AccessToken)
 		} else {
 			fmt.Fprint(os.Stderr, "You are not currently authenticated. Please log in to continue.")
@@ -27,17 +46,34 @@ func init() {
 	}
 }
 
-func refreshConfiguration(cfg config.Config) config.Config {
-	credhubClient, _ := initializeCredhubClient(cfg)
-	authObject := credhubClient.Auth
-	oauth := authObject.(*auth.OAuthStrategy)
-	err := oauth.Refresh()
+func refreshConfiguration(cfg config.Config) (config.Config, error) {
+	credhubClient, err := initializeCredhubClient(cfg)
+	if err != nil {
+		return cfg, err
+	}
+	oauth, ok := credhubClient.Auth.(*auth.OAuthStrategy)
+	if !ok {
+		return cfg, nil
+	}
+	err = oauth.Refresh()
 
 	if err != nil {
-		return cfg
+		return cfg, nil
 	}
 
 	cfg.AccessToken = oauth.AccessToken()
 	cfg.RefreshToken = oauth.RefreshToken()
-	return cfg
+	return cfg, nil
+}
+
+func execToken(cfg config.Config) (string, error) {
+	credhubClient, err := initializeCredhubClient(cfg)
+	if err != nil {
+		return "", err
+	}
+	exec, ok := credhubClient.Auth.(*auth.ExecStrategy)
+	if !ok {
+		return "", errors.NewRevokedTokenError()
+	}
+	return exec.Token(context.Background())
 }
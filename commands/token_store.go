@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"code.cloudfoundry.org/credhub-cli/config"
+)
+
+type TokenStoreCommand struct {
+	Args    TokenStoreArgs `positional-args:"yes"`
+	KeyFile string         `long:"key-file" description:"File holding at least 32 random bytes to encrypt the tokens with, instead of the passphrase in CREDHUB_TOKEN_PASSPHRASE"`
+}
+
+type TokenStoreArgs struct {
+	Store string `positional-arg-name:"STORE" description:"Where to save access and refresh tokens, either plaintext or encrypted-file"`
+}
+
+func (c *TokenStoreCommand) Execute([]string) error {
+	if c.Args.Store == "" {
+		store, err := config.TokenStoreName()
+		if err != nil {
+			return err
+		}
+		fmt.Println(store)
+		return nil
+	}
+
+	keyFile := c.KeyFile
+	if keyFile != "" {
+		var err error
+		keyFile, err = filepath.Abs(keyFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := config.UseTokenStore(c.Args.Store, keyFile); err != nil {
+		return err
+	}
+
+	fmt.Println("Tokens are saved in the token store:", c.Args.Store)
+	return nil
+}
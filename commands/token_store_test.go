@@ -0,0 +1,61 @@
+package commands_test
+
+import (
+	"net/http"
+	"os"
+
+	"code.cloudfoundry.org/credhub-cli/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Token store", func() {
+	passphrase := []string{"CREDHUB_TOKEN_PASSPHRASE=correct horse battery staple"}
+
+	BeforeEach(func() {
+		SetupServers(server, authServer)
+	})
+
+	It("encrypts the tokens saved by login", func() {
+		session := runCommand("token-store")
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say("plaintext"))
+
+		session = runCommandWithEnv(passphrase, "token-store", "encrypted-file")
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say("Tokens are saved in the token store: encrypted-file"))
+
+		authServer.AppendHandlers(
+			RespondWith(http.StatusOK, `{"access_token":"test-access-token","refresh_token":"test-refresh-token","token_type":"password","expires_in":123456789}`),
+		)
+		session = runCommandWithEnv(passphrase, "login", "-u", "test-username", "-p", "test-password")
+		Eventually(session).Should(Exit(0))
+
+		data, err := os.ReadFile(config.ConfigPath())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).NotTo(ContainSubstring("test-access-token"))
+
+		server.RouteToHandler("GET", "/api/v1/data",
+			CombineHandlers(
+				VerifyHeaderKV("Authorization", "Bearer test-access-token"),
+				RespondWith(http.StatusOK, `{"credentials": []}`),
+			),
+		)
+		session = runCommandWithEnv(passphrase, "find")
+		Eventually(session).Should(Exit(0))
+
+		session = runCommand("find")
+		Eventually(session).Should(Exit(1))
+		Expect(session.Err).To(Say("The saved tokens are encrypted with a passphrase or key file which is not available."))
+	})
+
+	It("rejects an unknown token store", func() {
+		session := runCommand("token-store", "keychain")
+
+		Eventually(session).Should(Exit(1))
+		Expect(session.Err).To(Say("The token store 'keychain' is not supported."))
+	})
+})
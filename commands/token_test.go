@@ -7,6 +7,7 @@ import (
 	"code.cloudfoundry.org/credhub-cli/config"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
 	. "github.com/onsi/gomega/gexec"
 	. "github.com/onsi/gomega/ghttp"
 )
@@ -119,5 +120,17 @@ var _ = Describe("Token", func() {
 			cfg := config.ReadConfig()
 			Expect(cfg.AccessToken).To(Equal(""))
 		})
+
+		It("returns an error if the client can not be configured", func() {
+			cfg := config.ReadConfig()
+			cfg.ApiURL = ""
+			config.WriteConfig(cfg)
+
+			session := runCommandWithEnv([]string{"CREDHUB_CLIENT=credhub_cli", "CREDHUB_SECRET=secret"}, "--token")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("An API target is not set."))
+			Expect(session.Out.Contents()).To(BeEmpty())
+		})
 	})
 })
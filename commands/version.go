@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"code.cloudfoundry.org/credhub-cli/config"
+	"code.cloudfoundry.org/credhub-cli/credhub"
 	"code.cloudfoundry.org/credhub-cli/version"
 )
 
@@ -16,7 +17,8 @@ func PrintVersion() error {
 	fmt.Println("CLI Version:", version.Version)
 
 	if cfg.ApiURL != "" {
-		credhubClient, err := initializeCredhubClient(cfg)
+		// ask the server, rather than reporting the version saved on login
+		credhubClient, err := initializeCredhubClient(cfg, credhub.ServerVersion(""))
 
 		if err == nil {
 			version, err := credhubClient.ServerVersion()
@@ -1,22 +1,63 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"path"
+	"slices"
+	"strconv"
 	"time"
 
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth/oidc"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth/uaa"
 	"code.cloudfoundry.org/credhub-cli/util"
 )
 
 const AuthClient = "credhub_cli"
 const AuthPassword = ""
 
+// AuthProviderOIDC is the AuthProvider of configs that log in with an OpenID Connect provider instead of UAA
+const AuthProviderOIDC = "oidc"
+
 type Config struct {
 	ConfigWithoutSecrets
 	ClientID     string
 	ClientSecret string
+
+	// Token is an access token from the CREDHUB_TOKEN environment variable, used instead of any other credentials
+	Token string
+
+	// Retries is how many times failed requests are retried, from the CREDHUB_RETRIES environment variable
+	Retries *int
+
+	// RetryPut allows PUT requests to be retried, from the CREDHUB_RETRY_PUT environment variable
+	RetryPut bool
+
+	// Target is the name of the target the config was read from, and is written to
+	Target string
+
+	// readTokens are the tokens when the config was read, so that WriteConfig can tell whether they were changed
+	readTokens *tokens
+}
+
+// tokens are the fields of the session, which another CLI process may have changed since the config was read
+type tokens struct {
+	AccessToken       string
+	RefreshToken      string
+	StaticToken       bool
+	AccessTokenExpiry *time.Time
+}
+
+func (cfg *Config) tokens() tokens {
+	return tokens{
+		AccessToken:       cfg.AccessToken,
+		RefreshToken:      cfg.RefreshToken,
+		StaticToken:       cfg.StaticToken,
+		AccessTokenExpiry: cfg.AccessTokenExpiry,
+	}
 }
 
 func ConfigDir() string {
@@ -30,14 +71,17 @@ func ConfigPath() string {
 func ReadConfig() Config {
 	c := Config{}
 
-	data, err := os.ReadFile(ConfigPath())
+	file, err := readConfigFile()
 	if err != nil {
-		if !os.IsNotExist(err) {
-			return c
-		}
+		return c
+	}
+
+	if err := file.loadTokens(); err != nil {
+		fmt.Fprintf(os.Stderr, "error reading saved tokens: %+v\n", err)
 	}
 
-	json.Unmarshal(data, &c)
+	c.Target = file.selectedTarget()
+	c.ConfigWithoutSecrets = file.Targets[c.Target]
 
 	if server, ok := os.LookupEnv("CREDHUB_SERVER"); ok {
 		if util.TokenIsPresent(c.AccessToken) {
@@ -59,13 +103,32 @@ b. Unset the "CREDHUB_SERVER" environment variable. Subsequent commands will use
 		c.AuthURL = ""
 		c.AccessToken = ""
 		c.RefreshToken = ""
+		c.ClientCertPath = ""
+		c.ClientKeyPath = ""
+		c.ExecCommand = nil
+		c.StaticToken = false
+		c.AccessTokenExpiry = nil
 	}
+
+	// taken before the remaining variables are read, since an invalid one returns early
+	readTokens := c.tokens()
+	c.readTokens = &readTokens
+
 	if client, ok := os.LookupEnv("CREDHUB_CLIENT"); ok {
 		c.ClientID = client
 	}
 	if clientSecret, ok := os.LookupEnv("CREDHUB_SECRET"); ok {
 		c.ClientSecret = clientSecret
 	}
+	if token, ok := os.LookupEnv("CREDHUB_TOKEN"); ok {
+		c.Token = token
+	}
+	if clientCert, ok := os.LookupEnv("CREDHUB_CLIENT_CERT"); ok {
+		c.ClientCertPath = clientCert
+	}
+	if clientKey, ok := os.LookupEnv("CREDHUB_CLIENT_KEY"); ok {
+		c.ClientKeyPath = clientKey
+	}
 	if caCert, ok := os.LookupEnv("CREDHUB_CA_CERT"); ok {
 		certs, err := ReadOrGetCaCerts([]string{caCert})
 		if err != nil {
@@ -82,25 +145,215 @@ b. Unset the "CREDHUB_SERVER" environment variable. Subsequent commands will use
 		}
 		c.HttpTimeout = &timeout
 	}
+	if retriesString, ok := os.LookupEnv("CREDHUB_RETRIES"); ok {
+		if retries, err := strconv.Atoi(retriesString); err != nil || retries < 0 {
+			fmt.Fprintf(os.Stderr, "error parsing Retries: %q is not a non-negative integer\n", retriesString)
+		} else {
+			c.Retries = &retries
+		}
+	}
+	if retryPutString, ok := os.LookupEnv("CREDHUB_RETRY_PUT"); ok {
+		if retryPut, err := strconv.ParseBool(retryPutString); err != nil {
+			fmt.Fprintf(os.Stderr, "error parsing RetryPut: %q is not a boolean\n", retryPutString)
+		} else {
+			c.RetryPut = retryPut
+		}
+	}
 
 	return c
 }
 
+// WriteConfig saves the config as its Target, or the selected target when it has none. The config of other targets is kept.
+//
+// When the config came from ReadConfig and its tokens have not been changed since, the saved tokens are kept,
+// so that tokens refreshed by a concurrent CLI process are not overwritten with older ones.
 func WriteConfig(c Config) error {
-	err := makeDirectory()
-	if err != nil {
-		return err
+	return updateConfigFile(func(file *configFile) error {
+		target := c.Target
+		if target == "" {
+			target = file.selectedTarget()
+		}
+
+		updated := ConvertConfigToConfigWithoutSecrets(c)
+		if saved, ok := file.Targets[target]; ok && c.readTokens != nil && *c.readTokens == c.tokens() {
+			updated.AccessToken = saved.AccessToken
+			updated.RefreshToken = saved.RefreshToken
+			updated.StaticToken = saved.StaticToken
+			updated.AccessTokenExpiry = saved.AccessTokenExpiry
+		}
+
+		file.Targets[target] = updated
+		return nil
+	})
+}
+
+// Client provides a CredHub API client which is configured and authenticates like the CLI, using the config file
+// of the CLI and the CREDHUB_* environment variables. Options override those from the config.
+func Client(options ...credhub.Option) (*credhub.CredHub, error) {
+	cfg := ReadConfig()
+	return cfg.Client(options...)
+}
+
+// Client provides a CredHub API client for the config, authenticated with the client credentials of the config
+// if it has them, or else as the CLI. Options override those from the config.
+func (cfg *Config) Client(options ...credhub.Option) (*credhub.CredHub, error) {
+	if err := ValidateConfig(*cfg); err != nil {
+		return nil, err
 	}
 
-	configWithoutSecrets := ConvertConfigToConfigWithoutSecrets(c)
+	clientId := cfg.ClientID
+	clientSecret := cfg.ClientSecret
+	usingClientCredentials := true
+	if clientId == "" {
+		clientId = AuthClient
+		clientSecret = AuthPassword
+		usingClientCredentials = false
+	}
 
-	data, err := json.Marshal(configWithoutSecrets)
-	if err != nil {
-		return err
+	cfgOptions := append([]credhub.Option{
+		credhub.AuthURL(cfg.AuthURL),
+		credhub.CaCerts(cfg.CaCerts...),
+		credhub.SkipTLSValidation(cfg.InsecureSkipVerify),
+		credhub.ServerVersion(cfg.ServerVersion),
+		credhub.SetHttpTimeout(cfg.HttpTimeout),
+		credhub.Retry(cfg.RetryPolicy()),
+		credhub.Logger(util.DebugLogger()),
+	}, cfg.AuthOptions(clientId, clientSecret, usingClientCredentials)...)
+
+	return credhub.New(cfg.ApiURL, append(cfgOptions, options...)...)
+}
+
+// RetryPolicy returns the policy for retrying requests to CredHub. Retries are disabled unless configured.
+//
+// PUT requests are only retried when RetryPut is set, because a retried PUT to /api/v1/data creates an additional
+// credential version if the first attempt reached the server.
+func (cfg *Config) RetryPolicy() credhub.RetryPolicy {
+	if cfg.Retries == nil || *cfg.Retries <= 0 {
+		return credhub.RetryPolicy{}
 	}
 
-	configPath := ConfigPath()
-	return os.WriteFile(configPath, data, 0600)
+	policy := credhub.DefaultRetryPolicy
+	policy.MaxAttempts = *cfg.Retries + 1
+	policy.RetryPut = cfg.RetryPut
+
+	return policy
+}
+
+// UsesClientCertificate reports whether requests to CredHub are authenticated with a client certificate instead of UAA
+func (cfg *Config) UsesClientCertificate() bool {
+	return cfg.ClientCertPath != "" && cfg.ClientKeyPath != ""
+}
+
+// UsesExecCredential reports whether access tokens are obtained by running an exec credential plugin instead of from UAA
+func (cfg *Config) UsesExecCredential() bool {
+	return len(cfg.ExecCommand) > 0
+}
+
+// AuthOptions returns the credhub.New() options which authenticate requests as configured, with a bearer token,
+// a client certificate, an exec credential plugin, or tokens from the auth server for the given client
+func (cfg *Config) AuthOptions(clientId, clientSecret string, usingClientCredentials bool) []credhub.Option {
+	switch {
+	case cfg.Token != "":
+		return []credhub.Option{
+			credhub.Auth(auth.BearerToken(cfg.Token)),
+		}
+	case cfg.StaticToken:
+		return []credhub.Option{
+			credhub.Auth(auth.BearerToken(cfg.AccessToken)),
+		}
+	case cfg.UsesClientCertificate():
+		return []credhub.Option{
+			credhub.ClientCert(cfg.ClientCertPath, cfg.ClientKeyPath),
+			credhub.Auth(auth.MutualTLS),
+		}
+	case cfg.UsesExecCredential():
+		return []credhub.Option{
+			credhub.Auth(cfg.execBuilder()),
+		}
+	default:
+		return []credhub.Option{
+			credhub.Auth(cfg.OAuthBuilder(clientId, clientSecret, usingClientCredentials)),
+		}
+	}
+}
+
+// execBuilder builds an ExecStrategy for the ExecCommand which starts with the access token saved for the target,
+// and saves the tokens printed by the command, so that the command does not run for every CLI process
+func (cfg *Config) execBuilder() auth.Builder {
+	builder := auth.Exec(cfg.ExecCommand[0], cfg.ExecCommand[1:]...)
+	target := cfg.Target
+	command := cfg.ExecCommand
+
+	return func(config auth.Config) (auth.Strategy, error) {
+		strategy, err := builder(config)
+		if err != nil {
+			return nil, err
+		}
+
+		exec := strategy.(*auth.ExecStrategy)
+		if util.TokenIsPresent(cfg.AccessToken) {
+			var expiry time.Time
+			if cfg.AccessTokenExpiry != nil {
+				expiry = *cfg.AccessTokenExpiry
+			}
+			exec.SetToken(cfg.AccessToken, expiry)
+		}
+		exec.OnToken = func(accessToken string, expiry time.Time) {
+			if err := saveExecToken(target, command, accessToken, expiry); err != nil {
+				fmt.Fprintf(os.Stderr, "error saving the exec credential token: %+v\n", err)
+			}
+		}
+
+		return exec, nil
+	}
+}
+
+// saveExecToken saves a token printed by command as the token of target, unless target has stopped using command
+func saveExecToken(target string, command []string, accessToken string, expiry time.Time) error {
+	return updateConfigFile(func(file *configFile) error {
+		if target == "" {
+			target = file.selectedTarget()
+		}
+
+		saved, ok := file.Targets[target]
+		if !ok || !slices.Equal(saved.ExecCommand, command) {
+			return nil
+		}
+
+		saved.AccessToken = accessToken
+		saved.AccessTokenExpiry = nil
+		if !expiry.IsZero() {
+			saved.AccessTokenExpiry = &expiry
+		}
+
+		file.Targets[target] = saved
+		return nil
+	})
+}
+
+// OAuthBuilder builds an OAuthStrategy for the configured AuthProvider, using the tokens in the config
+func (cfg *Config) OAuthBuilder(clientId, clientSecret string, usingClientCredentials bool) auth.Builder {
+	builder := auth.Uaa
+	if cfg.AuthProvider == AuthProviderOIDC {
+		builder = auth.OIDC
+	}
+	return builder(clientId, clientSecret, "", "", cfg.AccessToken, cfg.RefreshToken, usingClientCredentials)
+}
+
+// OAuthClient returns a client for the token grants of the configured AuthProvider
+func (cfg *Config) OAuthClient(httpClient *http.Client) auth.OAuthClient {
+	if cfg.AuthProvider == AuthProviderOIDC {
+		return &oidc.Client{
+			Issuer:       cfg.AuthURL,
+			Client:       httpClient,
+			ClientId:     AuthClient,
+			ClientSecret: AuthPassword,
+		}
+	}
+	return &uaa.Client{
+		AuthURL: cfg.AuthURL,
+		Client:  httpClient,
+	}
 }
 
 func RemoveConfig() error {
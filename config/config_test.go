@@ -5,11 +5,17 @@ package config_test
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"path"
+	"sync"
 	"time"
 
 	"code.cloudfoundry.org/credhub-cli/config"
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth/oidc"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth/uaa"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -72,6 +78,57 @@ var _ = Describe("Config", func() {
 			Expect(string(configFile)).NotTo(ContainSubstring(someClientID))
 			Expect(string(configFile)).NotTo(ContainSubstring(someClientSecret))
 		})
+
+		It("does not lose the changes of concurrent writers", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer GinkgoRecover()
+					defer wg.Done()
+
+					target := config.Config{Target: fmt.Sprintf("target-%d", i)}
+					target.ApiURL = fmt.Sprintf("https://credhub-%d.example.com", i)
+					Expect(config.WriteConfig(target)).To(Succeed())
+				}(i)
+			}
+			wg.Wait()
+
+			Expect(config.ListTargets()).To(HaveLen(20))
+
+			entries, err := os.ReadDir(config.ConfigDir())
+			Expect(err).NotTo(HaveOccurred())
+			for _, entry := range entries {
+				Expect(entry.Name()).NotTo(ContainSubstring(".tmp-"))
+			}
+		})
+
+		It("keeps tokens saved by another process since the config was read", func() {
+			cfg.AccessToken = "old-access-token"
+			cfg.RefreshToken = "old-refresh-token"
+			Expect(config.WriteConfig(cfg)).To(Succeed())
+
+			stale := config.ReadConfig()
+
+			refreshed := config.ReadConfig()
+			refreshed.AccessToken = "new-access-token"
+			refreshed.RefreshToken = "new-refresh-token"
+			Expect(config.WriteConfig(refreshed)).To(Succeed())
+
+			stale.ServerVersion = "2.9.0"
+			Expect(config.WriteConfig(stale)).To(Succeed())
+
+			saved := config.ReadConfig()
+			Expect(saved.ServerVersion).To(Equal("2.9.0"))
+			Expect(saved.AccessToken).To(Equal("new-access-token"))
+			Expect(saved.RefreshToken).To(Equal("new-refresh-token"))
+
+			saved.AccessToken = "revoked"
+			saved.RefreshToken = "revoked"
+			Expect(config.WriteConfig(saved)).To(Succeed())
+
+			Expect(config.ReadConfig().AccessToken).To(Equal("revoked"))
+		})
 	})
 
 	Describe("HttpTimeout", func() {
@@ -104,6 +161,168 @@ var _ = Describe("Config", func() {
 		})
 	})
 
+	Describe("#Client", func() {
+		It("authenticates with the client credentials of the config", func() {
+			cfg.ClientID = "some-client"
+			cfg.ClientSecret = "some-secret"
+
+			client, err := cfg.Client()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.ApiURL).To(Equal("http://api.example.com"))
+			oauth, ok := client.Auth.(*auth.OAuthStrategy)
+			Expect(ok).To(BeTrue())
+			Expect(oauth.ClientId).To(Equal("some-client"))
+			Expect(oauth.ClientCredentialRefresh).To(BeTrue())
+		})
+
+		It("authenticates with the saved tokens as the CLI", func() {
+			cfg.AccessToken = "some-access-token"
+
+			client, err := cfg.Client()
+			Expect(err).NotTo(HaveOccurred())
+
+			oauth := client.Auth.(*auth.OAuthStrategy)
+			Expect(oauth.ClientId).To(Equal(config.AuthClient))
+			Expect(oauth.AccessToken()).To(Equal("some-access-token"))
+		})
+
+		It("applies the given options after those of the config", func() {
+			cfg.AccessToken = "some-access-token"
+
+			client, err := cfg.Client(credhub.Auth(auth.BearerToken("other-token")))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.Auth).To(BeAssignableToTypeOf(&auth.BearerTokenStrategy{}))
+		})
+
+		It("requires the config to be authenticated", func() {
+			_, err := cfg.Client()
+
+			Expect(err).To(MatchError("You are not currently authenticated. Please log in to continue."))
+		})
+	})
+
+	Describe("#RetryPolicy", func() {
+		It("disables retries when none are configured", func() {
+			Expect(cfg.RetryPolicy().MaxAttempts).To(Equal(0))
+		})
+
+		It("retries idempotent requests the configured number of times", func() {
+			retries := 2
+			cfg.Retries = &retries
+
+			policy := cfg.RetryPolicy()
+			Expect(policy.MaxAttempts).To(Equal(3))
+			Expect(policy.RetryPut).To(BeFalse())
+			Expect(policy.MaxBackoff).To(Equal(credhub.DefaultRetryPolicy.MaxBackoff))
+		})
+
+		It("retries PUT requests when RetryPut is set", func() {
+			retries := 2
+			cfg.Retries = &retries
+			cfg.RetryPut = true
+
+			Expect(cfg.RetryPolicy().RetryPut).To(BeTrue())
+		})
+	})
+
+	Describe("#OAuthClient", func() {
+		It("uses UAA by default", func() {
+			client, ok := cfg.OAuthClient(http.DefaultClient).(*uaa.Client)
+			Expect(ok).To(BeTrue())
+			Expect(client.AuthURL).To(Equal("http://auth.example.com"))
+		})
+
+		It("discovers the OpenID Connect provider at the auth URL", func() {
+			cfg.AuthProvider = config.AuthProviderOIDC
+
+			client, ok := cfg.OAuthClient(http.DefaultClient).(*oidc.Client)
+			Expect(ok).To(BeTrue())
+			Expect(client.Issuer).To(Equal("http://auth.example.com"))
+			Expect(client.ClientId).To(Equal(config.AuthClient))
+		})
+	})
+
+	Describe("#ReadConfig", func() {
+		AfterEach(func() {
+			os.Unsetenv("CREDHUB_RETRIES")
+			os.Unsetenv("CREDHUB_RETRY_PUT")
+			os.Unsetenv("CREDHUB_CLIENT_CERT")
+			os.Unsetenv("CREDHUB_CLIENT_KEY")
+		})
+
+		It("reads the client certificate from CREDHUB_CLIENT_CERT and CREDHUB_CLIENT_KEY", func() {
+			os.Setenv("CREDHUB_CLIENT_CERT", "/some/cert.pem")
+			os.Setenv("CREDHUB_CLIENT_KEY", "/some/key.pem")
+
+			cfg := config.ReadConfig()
+
+			Expect(cfg.ClientCertPath).To(Equal("/some/cert.pem"))
+			Expect(cfg.ClientKeyPath).To(Equal("/some/key.pem"))
+			Expect(cfg.UsesClientCertificate()).To(BeTrue())
+		})
+
+		It("reads the number of retries from CREDHUB_RETRIES", func() {
+			os.Setenv("CREDHUB_RETRIES", "4")
+
+			Expect(*config.ReadConfig().Retries).To(Equal(4))
+		})
+
+		It("ignores an invalid number of retries", func() {
+			os.Setenv("CREDHUB_RETRIES", "-1")
+			os.Setenv("CREDHUB_RETRY_PUT", "true")
+
+			cfg := config.ReadConfig()
+
+			Expect(cfg.Retries).To(BeNil())
+			Expect(cfg.RetryPut).To(BeTrue())
+		})
+
+		It("keeps tokens saved by another process when the number of retries is invalid", func() {
+			cfg.AccessToken = "old-access-token"
+			Expect(config.WriteConfig(cfg)).To(Succeed())
+			os.Setenv("CREDHUB_RETRIES", "some-retries")
+
+			stale := config.ReadConfig()
+
+			refreshed := config.ReadConfig()
+			refreshed.AccessToken = "new-access-token"
+			Expect(config.WriteConfig(refreshed)).To(Succeed())
+
+			Expect(config.WriteConfig(stale)).To(Succeed())
+
+			Expect(config.ReadConfig().AccessToken).To(Equal("new-access-token"))
+		})
+
+		It("does not save the retry settings", func() {
+			os.Setenv("CREDHUB_RETRIES", "4")
+			os.Setenv("CREDHUB_RETRY_PUT", "true")
+			Expect(config.WriteConfig(config.ReadConfig())).To(Succeed())
+
+			os.Unsetenv("CREDHUB_RETRIES")
+			os.Unsetenv("CREDHUB_RETRY_PUT")
+			cfg := config.ReadConfig()
+
+			Expect(cfg.Retries).To(BeNil())
+			Expect(cfg.RetryPut).To(BeFalse())
+		})
+
+		It("reads whether to retry PUT requests from CREDHUB_RETRY_PUT", func() {
+			Expect(config.ReadConfig().RetryPut).To(BeFalse())
+
+			os.Setenv("CREDHUB_RETRY_PUT", "true")
+
+			Expect(config.ReadConfig().RetryPut).To(BeTrue())
+		})
+
+		It("ignores an invalid CREDHUB_RETRY_PUT", func() {
+			os.Setenv("CREDHUB_RETRY_PUT", "sometimes")
+
+			Expect(config.ReadConfig().RetryPut).To(BeFalse())
+		})
+	})
+
 	Describe("#UpdateTrustedCAs", func() {
 		It("reads multiple certs", func() {
 			ca1, err := os.ReadFile("../test/server-tls-ca.pem")
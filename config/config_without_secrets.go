@@ -11,6 +11,14 @@ type ConfigWithoutSecrets struct {
 	CaCerts            []string
 	ServerVersion      string
 	HttpTimeout        *time.Duration
+	ClientCertPath     string
+	ClientKeyPath      string
+	AuthProvider       string
+	ExecCommand        []string
+	StaticToken        bool
+
+	// AccessTokenExpiry is when the AccessToken printed by the ExecCommand expires, nil when unknown
+	AccessTokenExpiry *time.Time `json:",omitempty"`
 }
 
 func ConvertConfigToConfigWithoutSecrets(config Config) ConfigWithoutSecrets {
@@ -23,5 +31,11 @@ func ConvertConfigToConfigWithoutSecrets(config Config) ConfigWithoutSecrets {
 		CaCerts:            config.CaCerts,
 		ServerVersion:      config.ServerVersion,
 		HttpTimeout:        config.HttpTimeout,
+		ClientCertPath:     config.ClientCertPath,
+		ClientKeyPath:      config.ClientKeyPath,
+		AuthProvider:       config.AuthProvider,
+		ExecCommand:        config.ExecCommand,
+		StaticToken:        config.StaticToken,
+		AccessTokenExpiry:  config.AccessTokenExpiry,
 	}
 }
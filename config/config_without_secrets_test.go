@@ -25,6 +25,11 @@ var _ = Describe("ConfigWithoutSecrets", func() {
 					CaCerts:            []string{"cert1", "cert2"},
 					ServerVersion:      "version",
 					HttpTimeout:        &timeout,
+					ClientCertPath:     "/cert.pem",
+					ClientKeyPath:      "/key.pem",
+					AuthProvider:       config.AuthProviderOIDC,
+					ExecCommand:        []string{"credhub-token", "--audience", "credhub"},
+					StaticToken:        true,
 				},
 				ClientID:     "clientID",
 				ClientSecret: "clientSecret",
@@ -39,6 +44,11 @@ var _ = Describe("ConfigWithoutSecrets", func() {
 				CaCerts:            []string{"cert1", "cert2"},
 				ServerVersion:      "version",
 				HttpTimeout:        &timeout,
+				ClientCertPath:     "/cert.pem",
+				ClientKeyPath:      "/key.pem",
+				AuthProvider:       config.AuthProviderOIDC,
+				ExecCommand:        []string{"credhub-token", "--audience", "credhub"},
+				StaticToken:        true,
 			}
 
 			actualState := config.ConvertConfigToConfigWithoutSecrets(cliConfig)
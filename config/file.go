@@ -0,0 +1,162 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// configFile is the format of the config file, which holds the config of each target
+type configFile struct {
+	CurrentTarget string
+	Targets       map[string]ConfigWithoutSecrets
+
+	// TokenStore is where the tokens of the targets are saved, TokenStorePlaintext when empty
+	TokenStore   string `json:",omitempty"`
+	TokenKeyFile string `json:",omitempty"`
+}
+
+func readConfigFile() (configFile, error) {
+	file := configFile{}
+
+	data, err := os.ReadFile(ConfigPath())
+	if os.IsNotExist(err) {
+		file.Targets = map[string]ConfigWithoutSecrets{}
+		return file, nil
+	} else if err != nil {
+		return file, err
+	}
+
+	json.Unmarshal(data, &file)
+
+	if file.Targets == nil {
+		// the file was written without named targets, so it holds the config of a single server
+		var legacy ConfigWithoutSecrets
+		json.Unmarshal(data, &legacy)
+
+		file.CurrentTarget = DefaultTarget
+		file.Targets = map[string]ConfigWithoutSecrets{DefaultTarget: legacy}
+	}
+
+	return file, nil
+}
+
+// updateConfigFile reads the config file, applies update and writes it back. Concurrent CLI processes take
+// turns through an advisory lock, so that none of them overwrites the changes of another.
+//
+// The file is replaced by renaming a temporary file, so that it is never read while partially written.
+func updateConfigFile(update func(*configFile) error) error {
+	err := makeDirectory()
+	if err != nil {
+		return err
+	}
+
+	lock, err := os.OpenFile(ConfigPath()+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+
+	if err := lockFile(lock); err != nil {
+		return err
+	}
+	defer unlockFile(lock)
+
+	file, err := readConfigFile()
+	if err != nil {
+		return err
+	}
+
+	if err := file.loadTokens(); err != nil {
+		return err
+	}
+
+	if err := update(&file); err != nil {
+		return err
+	}
+
+	if err := file.saveTokens(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomically(ConfigPath(), data); err != nil {
+		return err
+	}
+
+	if file.TokenStore != TokenStoreEncryptedFile {
+		// the tokens were moved to the config file
+		if err := os.Remove(tokensPath()); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *configFile) tokenStore() TokenStore {
+	if f.TokenStore == TokenStoreEncryptedFile {
+		return &EncryptedFileTokenStore{
+			Path:       tokensPath(),
+			KeyFile:    f.TokenKeyFile,
+			Passphrase: os.Getenv("CREDHUB_TOKEN_PASSPHRASE"),
+		}
+	}
+	return &plaintextTokenStore{file: f}
+}
+
+// loadTokens sets the tokens of the targets from the token store
+func (f *configFile) loadTokens() error {
+	tokens, err := f.tokenStore().Load()
+	if err != nil {
+		return err
+	}
+
+	for name, target := range f.Targets {
+		target.AccessToken = tokens[name].AccessToken
+		target.RefreshToken = tokens[name].RefreshToken
+		f.Targets[name] = target
+	}
+	return nil
+}
+
+// saveTokens moves the tokens of the targets to the token store
+func (f *configFile) saveTokens() error {
+	tokens := map[string]Tokens{}
+	for name, target := range f.Targets {
+		if target.AccessToken != "" || target.RefreshToken != "" {
+			tokens[name] = Tokens{AccessToken: target.AccessToken, RefreshToken: target.RefreshToken}
+		}
+
+		target.AccessToken = ""
+		target.RefreshToken = ""
+		f.Targets[name] = target
+	}
+	return f.tokenStore().Save(tokens)
+}
+
+func writeFileAtomically(filename string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filename)
+}
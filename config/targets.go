@@ -0,0 +1,141 @@
+package config
+
+import (
+	"os"
+	"sort"
+
+	"code.cloudfoundry.org/credhub-cli/errors"
+)
+
+// DefaultTarget is the name of the target used when none has been selected, which also holds
+// the config written by versions of the CLI without named targets
+const DefaultTarget = "default"
+
+// Target is a named CredHub server saved in the config file
+type Target struct {
+	Name    string `json:"name" yaml:"name"`
+	ApiURL  string `json:"api_url" yaml:"api_url"`
+	Current bool   `json:"current" yaml:"current"`
+}
+
+// selectedTarget returns the name of the target selected with CREDHUB_TARGET, or else the current target
+func (f *configFile) selectedTarget() string {
+	if target, ok := os.LookupEnv("CREDHUB_TARGET"); ok && target != "" {
+		return target
+	}
+	if f.CurrentTarget != "" {
+		return f.CurrentTarget
+	}
+	return DefaultTarget
+}
+
+// ReadTarget returns the saved config of the named target, ignoring the CREDHUB_* environment variables
+func ReadTarget(name string) (Config, error) {
+	file, err := readConfigFile()
+	if err != nil {
+		return Config{}, err
+	}
+
+	if err := file.loadTokens(); err != nil {
+		return Config{}, err
+	}
+
+	target, ok := file.Targets[name]
+	if !ok {
+		return Config{}, errors.NewTargetNotFoundError(name)
+	}
+
+	return Config{ConfigWithoutSecrets: target, Target: name}, nil
+}
+
+// ListTargets returns the saved targets, sorted by name
+func ListTargets() ([]Target, error) {
+	file, err := readConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	current := file.CurrentTarget
+	if current == "" {
+		current = DefaultTarget
+	}
+
+	targets := []Target{}
+	for name, target := range file.Targets {
+		targets = append(targets, Target{
+			Name:    name,
+			ApiURL:  target.ApiURL,
+			Current: name == current,
+		})
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Name < targets[j].Name
+	})
+
+	return targets, nil
+}
+
+// UseTarget makes the named target the current target, which commands are sent to unless another is selected
+func UseTarget(name string) error {
+	return updateConfigFile(func(file *configFile) error {
+		if _, ok := file.Targets[name]; !ok {
+			return errors.NewTargetNotFoundError(name)
+		}
+
+		file.CurrentTarget = name
+		return nil
+	})
+}
+
+// DeleteTarget removes the named target. When it is the current target, commands are sent to the default target
+// again. It returns the name of the target selected afterwards, which is empty when no saved target is selected.
+func DeleteTarget(name string) (string, error) {
+	selected := ""
+	err := updateConfigFile(func(file *configFile) error {
+		if _, ok := file.Targets[name]; !ok {
+			return errors.NewTargetNotFoundError(name)
+		}
+
+		delete(file.Targets, name)
+		if file.CurrentTarget == name {
+			file.CurrentTarget = ""
+		}
+
+		if _, ok := file.Targets[file.selectedTarget()]; ok {
+			selected = file.selectedTarget()
+		}
+		return nil
+	})
+	return selected, err
+}
+
+// TokenStoreName returns the name of the token store which the tokens of the targets are saved in
+func TokenStoreName() (string, error) {
+	file, err := readConfigFile()
+	if err != nil {
+		return "", err
+	}
+
+	if file.TokenStore == "" {
+		return TokenStorePlaintext, nil
+	}
+	return file.TokenStore, nil
+}
+
+// UseTokenStore moves the tokens of the targets to the named token store. The encrypted-file store derives
+// its key from keyFile when given, and otherwise from the passphrase in CREDHUB_TOKEN_PASSPHRASE.
+func UseTokenStore(name, keyFile string) error {
+	if name != TokenStorePlaintext && name != TokenStoreEncryptedFile {
+		return errors.NewUnknownTokenStoreError(name)
+	}
+
+	return updateConfigFile(func(file *configFile) error {
+		file.TokenStore = ""
+		file.TokenKeyFile = ""
+		if name == TokenStoreEncryptedFile {
+			file.TokenStore = name
+			file.TokenKeyFile = keyFile
+		}
+		return nil
+	})
+}
@@ -0,0 +1,118 @@
+//go:build !windows
+// +build !windows
+
+package config_test
+
+import (
+	"os"
+
+	"code.cloudfoundry.org/credhub-cli/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Targets", func() {
+	BeforeEach(func() {
+		homeDir, err := os.MkdirTemp("", "credhub-cli-test")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(os.RemoveAll, homeDir)
+
+		os.Setenv("HOME", homeDir)
+		DeferCleanup(os.Unsetenv, "CREDHUB_TARGET")
+	})
+
+	writeTarget := func(name, apiURL, accessToken string) {
+		cfg := config.Config{Target: name}
+		cfg.ApiURL = apiURL
+		cfg.AccessToken = accessToken
+		Expect(config.WriteConfig(cfg)).To(Succeed())
+	}
+
+	It("migrates a config written without targets into the default target", func() {
+		Expect(os.MkdirAll(config.ConfigDir(), 0755)).To(Succeed())
+		Expect(os.WriteFile(config.ConfigPath(), []byte(`{"ApiURL": "https://credhub.example.com", "AccessToken": "some-token"}`), 0600)).To(Succeed())
+
+		cfg := config.ReadConfig()
+		Expect(cfg.Target).To(Equal(config.DefaultTarget))
+		Expect(cfg.ApiURL).To(Equal("https://credhub.example.com"))
+		Expect(cfg.AccessToken).To(Equal("some-token"))
+
+		writeTarget("prod", "https://prod.example.com", "prod-token")
+
+		Expect(config.ListTargets()).To(Equal([]config.Target{
+			{Name: config.DefaultTarget, ApiURL: "https://credhub.example.com", Current: true},
+			{Name: "prod", ApiURL: "https://prod.example.com"},
+		}))
+	})
+
+	It("keeps a config and session for each target", func() {
+		writeTarget("dev", "https://dev.example.com", "dev-token")
+		writeTarget("prod", "https://prod.example.com", "prod-token")
+		Expect(config.UseTarget("dev")).To(Succeed())
+
+		cfg := config.ReadConfig()
+		Expect(cfg.Target).To(Equal("dev"))
+		Expect(cfg.AccessToken).To(Equal("dev-token"))
+
+		cfg.AccessToken = "new-dev-token"
+		Expect(config.WriteConfig(cfg)).To(Succeed())
+
+		prod, err := config.ReadTarget("prod")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(prod.AccessToken).To(Equal("prod-token"))
+		Expect(config.ReadConfig().AccessToken).To(Equal("new-dev-token"))
+	})
+
+	It("does not make a written target the current target", func() {
+		writeTarget("dev", "https://dev.example.com", "dev-token")
+
+		Expect(config.ReadConfig().Target).To(Equal(config.DefaultTarget))
+		Expect(config.ListTargets()).To(Equal([]config.Target{
+			{Name: "dev", ApiURL: "https://dev.example.com"},
+		}))
+	})
+
+	It("reads the target selected with CREDHUB_TARGET", func() {
+		writeTarget("dev", "https://dev.example.com", "dev-token")
+		writeTarget("prod", "https://prod.example.com", "prod-token")
+
+		os.Setenv("CREDHUB_TARGET", "prod")
+
+		cfg := config.ReadConfig()
+		Expect(cfg.Target).To(Equal("prod"))
+		Expect(cfg.ApiURL).To(Equal("https://prod.example.com"))
+	})
+
+	It("switches and deletes targets", func() {
+		writeTarget("dev", "https://dev.example.com", "dev-token")
+		writeTarget("prod", "https://prod.example.com", "prod-token")
+
+		Expect(config.UseTarget("prod")).To(Succeed())
+		Expect(config.ReadConfig().ApiURL).To(Equal("https://prod.example.com"))
+
+		Expect(config.DeleteTarget("prod")).To(BeEmpty())
+		Expect(config.ReadConfig().Target).To(Equal(config.DefaultTarget))
+		Expect(config.ReadConfig().ApiURL).To(BeEmpty())
+		Expect(config.ListTargets()).To(Equal([]config.Target{
+			{Name: "dev", ApiURL: "https://dev.example.com"},
+		}))
+	})
+
+	It("returns the default target after the current target is deleted", func() {
+		writeTarget(config.DefaultTarget, "https://credhub.example.com", "some-token")
+		writeTarget("prod", "https://prod.example.com", "prod-token")
+		Expect(config.UseTarget("prod")).To(Succeed())
+
+		Expect(config.DeleteTarget("prod")).To(Equal(config.DefaultTarget))
+		Expect(config.ReadConfig().ApiURL).To(Equal("https://credhub.example.com"))
+	})
+
+	It("returns an error for a target which does not exist", func() {
+		Expect(config.UseTarget("missing")).To(MatchError(ContainSubstring("The target 'missing' does not exist.")))
+		_, err := config.DeleteTarget("missing")
+		Expect(err).To(MatchError(ContainSubstring("The target 'missing' does not exist.")))
+
+		_, err = config.ReadTarget("missing")
+		Expect(err).To(MatchError(ContainSubstring("The target 'missing' does not exist.")))
+	})
+})
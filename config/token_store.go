@@ -0,0 +1,263 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+
+	"code.cloudfoundry.org/credhub-cli/errors"
+)
+
+const (
+	// TokenStorePlaintext keeps tokens in the config file. It is the default.
+	TokenStorePlaintext = "plaintext"
+
+	// TokenStoreEncryptedFile keeps tokens in a file encrypted with a passphrase or key file
+	TokenStoreEncryptedFile = "encrypted-file"
+)
+
+// Tokens are the access and refresh tokens of a target
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+// TokenStore saves the tokens of each target, by target name
+type TokenStore interface {
+	Load() (map[string]Tokens, error)
+	Save(tokens map[string]Tokens) error
+}
+
+// plaintextTokenStore keeps tokens in the config file, as versions of the CLI without token stores did
+type plaintextTokenStore struct {
+	file *configFile
+}
+
+func (s *plaintextTokenStore) Load() (map[string]Tokens, error) {
+	tokens := map[string]Tokens{}
+	for name, target := range s.file.Targets {
+		tokens[name] = Tokens{AccessToken: target.AccessToken, RefreshToken: target.RefreshToken}
+	}
+	return tokens, nil
+}
+
+func (s *plaintextTokenStore) Save(tokens map[string]Tokens) error {
+	for name, target := range s.file.Targets {
+		target.AccessToken = tokens[name].AccessToken
+		target.RefreshToken = tokens[name].RefreshToken
+		s.file.Targets[name] = target
+	}
+	return nil
+}
+
+const (
+	kdfPBKDF2 = "pbkdf2-sha256"
+	kdfHKDF   = "hkdf-sha256"
+
+	// pbkdf2Iterations follows the OWASP recommendation for PBKDF2-HMAC-SHA256
+	pbkdf2Iterations = 600000
+
+	minKeyFileSize = 32
+)
+
+// EncryptedFileTokenStore keeps tokens in a file encrypted with AES-256-GCM. The key is derived from the
+// contents of KeyFile when it is set, and otherwise from Passphrase.
+type EncryptedFileTokenStore struct {
+	Path       string
+	KeyFile    string
+	Passphrase string
+
+	// kdf and salt are reused when saving, so that the key is only derived once
+	kdf  string
+	salt []byte
+}
+
+var _ TokenStore = new(EncryptedFileTokenStore)
+
+// encryptedTokens is the format of the encrypted file
+type encryptedTokens struct {
+	Version    int
+	KDF        string
+	Iterations int `json:",omitempty"`
+	Salt       []byte
+	Nonce      []byte
+	Ciphertext []byte
+}
+
+// derivedKeys holds the keys derived by this process, since PBKDF2 is deliberately slow
+var derivedKeys sync.Map
+
+func tokensPath() string {
+	return path.Join(ConfigDir(), "tokens.enc")
+}
+
+// Load decrypts the tokens. There are none when the file does not exist.
+func (s *EncryptedFileTokenStore) Load() (map[string]Tokens, error) {
+	tokens := map[string]Tokens{}
+
+	data, err := os.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return tokens, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var envelope encryptedTokens
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("the token file %s is corrupted: %w", s.Path, err)
+	}
+
+	key, err := s.key(envelope.KDF, envelope.Salt, envelope.Iterations)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, envelope.Nonce, envelope.Ciphertext, nil)
+	if err != nil {
+		return nil, errors.NewTokenStoreDecryptError()
+	}
+
+	if err := json.Unmarshal(plaintext, &tokens); err != nil {
+		return nil, err
+	}
+
+	s.kdf = envelope.KDF
+	s.salt = envelope.Salt
+
+	return tokens, nil
+}
+
+// Save encrypts the tokens, replacing the file
+func (s *EncryptedFileTokenStore) Save(tokens map[string]Tokens) error {
+	kdf := kdfPBKDF2
+	if s.KeyFile != "" {
+		kdf = kdfHKDF
+	}
+
+	if s.salt == nil {
+		// reuse the salt of the file, so that the key derived when loading the tokens is reused
+		if data, err := os.ReadFile(s.Path); err == nil {
+			var envelope encryptedTokens
+			if json.Unmarshal(data, &envelope) == nil {
+				s.kdf = envelope.KDF
+				s.salt = envelope.Salt
+			}
+		}
+	}
+
+	salt := s.salt
+	if kdf != s.kdf || salt == nil {
+		salt = make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+	}
+
+	key, err := s.key(kdf, salt, pbkdf2Iterations)
+	if err != nil {
+		return err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+
+	envelope := encryptedTokens{
+		Version: 1,
+		KDF:     kdf,
+		Salt:    salt,
+		Nonce:   make([]byte, aead.NonceSize()),
+	}
+	if kdf == kdfPBKDF2 {
+		envelope.Iterations = pbkdf2Iterations
+	}
+	if _, err := rand.Read(envelope.Nonce); err != nil {
+		return err
+	}
+	envelope.Ciphertext = aead.Seal(nil, envelope.Nonce, plaintext, nil)
+
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomically(s.Path, data); err != nil {
+		return err
+	}
+
+	s.kdf = kdf
+	s.salt = salt
+
+	return nil
+}
+
+func (s *EncryptedFileTokenStore) key(kdf string, salt []byte, iterations int) ([]byte, error) {
+	var secret []byte
+	switch kdf {
+	case kdfHKDF:
+		if s.KeyFile == "" {
+			return nil, errors.NewTokenStoreKeyMissingError()
+		}
+		keyFile, err := os.ReadFile(s.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if len(keyFile) < minKeyFileSize {
+			return nil, fmt.Errorf("the token key file %s must hold at least %d bytes", s.KeyFile, minKeyFileSize)
+		}
+		secret = keyFile
+	case kdfPBKDF2:
+		if s.Passphrase == "" {
+			return nil, errors.NewTokenStoreKeyMissingError()
+		}
+		secret = []byte(s.Passphrase)
+	default:
+		return nil, fmt.Errorf("the token file %s uses an unsupported key derivation %q", s.Path, kdf)
+	}
+
+	cacheKey := fmt.Sprintf("%s/%x/%d/%x", kdf, salt, iterations, sha256.Sum256(secret))
+	if key, ok := derivedKeys.Load(cacheKey); ok {
+		return key.([]byte), nil
+	}
+
+	var key []byte
+	var err error
+	if kdf == kdfHKDF {
+		key, err = hkdf.Key(sha256.New, secret, salt, "credhub-cli tokens", 32)
+	} else {
+		key, err = pbkdf2.Key(sha256.New, string(secret), salt, iterations, 32)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	derivedKeys.Store(cacheKey, key)
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
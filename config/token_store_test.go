@@ -0,0 +1,107 @@
+//go:build !windows
+// +build !windows
+
+package config_test
+
+import (
+	"os"
+	"path"
+
+	"code.cloudfoundry.org/credhub-cli/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Token stores", func() {
+	var homeDir string
+
+	BeforeEach(func() {
+		var err error
+		homeDir, err = os.MkdirTemp("", "credhub-cli-test")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(os.RemoveAll, homeDir)
+
+		os.Setenv("HOME", homeDir)
+		DeferCleanup(os.Unsetenv, "CREDHUB_TOKEN_PASSPHRASE")
+
+		cfg := config.Config{}
+		cfg.ApiURL = "https://credhub.example.com"
+		cfg.AccessToken = "some-access-token"
+		cfg.RefreshToken = "some-refresh-token"
+		Expect(config.WriteConfig(cfg)).To(Succeed())
+	})
+
+	configFile := func() string {
+		data, err := os.ReadFile(config.ConfigPath())
+		Expect(err).NotTo(HaveOccurred())
+		return string(data)
+	}
+
+	It("keeps tokens in the config file by default", func() {
+		Expect(config.TokenStoreName()).To(Equal(config.TokenStorePlaintext))
+		Expect(configFile()).To(ContainSubstring("some-refresh-token"))
+	})
+
+	It("encrypts tokens with a passphrase", func() {
+		os.Setenv("CREDHUB_TOKEN_PASSPHRASE", "correct horse battery staple")
+
+		Expect(config.UseTokenStore(config.TokenStoreEncryptedFile, "")).To(Succeed())
+
+		Expect(config.TokenStoreName()).To(Equal(config.TokenStoreEncryptedFile))
+		Expect(configFile()).NotTo(ContainSubstring("some-refresh-token"))
+		encrypted, err := os.ReadFile(path.Join(config.ConfigDir(), "tokens.enc"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(encrypted)).NotTo(ContainSubstring("some-refresh-token"))
+
+		cfg := config.ReadConfig()
+		Expect(cfg.AccessToken).To(Equal("some-access-token"))
+		Expect(cfg.RefreshToken).To(Equal("some-refresh-token"))
+
+		cfg.AccessToken = "new-access-token"
+		Expect(config.WriteConfig(cfg)).To(Succeed())
+		Expect(config.ReadConfig().AccessToken).To(Equal("new-access-token"))
+
+		os.Setenv("CREDHUB_TOKEN_PASSPHRASE", "wrong passphrase")
+		Expect(config.ReadConfig().AccessToken).To(BeEmpty())
+		Expect(config.WriteConfig(cfg)).To(MatchError(ContainSubstring("The saved tokens could not be decrypted.")))
+
+		os.Unsetenv("CREDHUB_TOKEN_PASSPHRASE")
+		_, err = config.ReadTarget(config.DefaultTarget)
+		Expect(err).To(MatchError(ContainSubstring("The saved tokens are encrypted with a passphrase or key file which is not available.")))
+	})
+
+	It("encrypts tokens with a key file", func() {
+		keyFile := path.Join(homeDir, "token.key")
+		Expect(os.WriteFile(keyFile, []byte("0123456789abcdef0123456789abcdef"), 0600)).To(Succeed())
+
+		Expect(config.UseTokenStore(config.TokenStoreEncryptedFile, keyFile)).To(Succeed())
+
+		Expect(configFile()).NotTo(ContainSubstring("some-access-token"))
+		Expect(config.ReadConfig().AccessToken).To(Equal("some-access-token"))
+	})
+
+	It("rejects a short key file", func() {
+		keyFile := path.Join(homeDir, "token.key")
+		Expect(os.WriteFile(keyFile, []byte("too short"), 0600)).To(Succeed())
+
+		err := config.UseTokenStore(config.TokenStoreEncryptedFile, keyFile)
+
+		Expect(err).To(MatchError("the token key file " + keyFile + " must hold at least 32 bytes"))
+		Expect(config.TokenStoreName()).To(Equal(config.TokenStorePlaintext))
+	})
+
+	It("moves tokens back to the config file", func() {
+		os.Setenv("CREDHUB_TOKEN_PASSPHRASE", "correct horse battery staple")
+		Expect(config.UseTokenStore(config.TokenStoreEncryptedFile, "")).To(Succeed())
+
+		Expect(config.UseTokenStore(config.TokenStorePlaintext, "")).To(Succeed())
+
+		Expect(configFile()).To(ContainSubstring("some-refresh-token"))
+		_, err := os.Stat(path.Join(config.ConfigDir(), "tokens.enc"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("rejects an unknown token store", func() {
+		Expect(config.UseTokenStore("keychain", "")).To(MatchError(ContainSubstring("The token store 'keychain' is not supported.")))
+	})
+})
@@ -6,7 +6,7 @@ func ValidateConfig(c Config) error {
 	err := ValidateConfigApi(c)
 	if err != nil {
 		return err
-	} else if (c.AccessToken == "" || c.AccessToken == "revoked") && c.ClientID == "" {
+	} else if (c.AccessToken == "" || c.AccessToken == "revoked") && c.ClientID == "" && c.Token == "" && !c.UsesClientCertificate() && !c.UsesExecCredential() {
 		return errors.NewRevokedTokenError()
 	}
 
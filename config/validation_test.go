@@ -35,6 +35,31 @@ var _ = Describe("Config validation", func() {
 		Expect(config.ValidateConfig(cfg)).To(Equal(errors.New("You are not currently authenticated. Please log in to continue.")))
 	})
 
+	It("does not require a token when a client certificate is configured", func() {
+		cfg := config.Config{}
+		cfg.ApiURL = "http://api.example.com"
+		cfg.ClientCertPath = "/some/cert.pem"
+		cfg.ClientKeyPath = "/some/key.pem"
+
+		Expect(config.ValidateConfig(cfg)).To(BeNil())
+	})
+
+	It("does not require a token when an exec credential plugin is configured", func() {
+		cfg := config.Config{}
+		cfg.ApiURL = "http://api.example.com"
+		cfg.ExecCommand = []string{"credhub-token"}
+
+		Expect(config.ValidateConfig(cfg)).To(BeNil())
+	})
+
+	It("does not require a saved token when CREDHUB_TOKEN is set", func() {
+		cfg := config.Config{}
+		cfg.ApiURL = "http://api.example.com"
+		cfg.Token = "some-token"
+
+		Expect(config.ValidateConfig(cfg)).To(BeNil())
+	})
+
 	It("requires a non-empty token", func() {
 		cfg := config.Config{}
 		cfg.ApiURL = "http://api.example.com"
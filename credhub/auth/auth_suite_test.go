@@ -1,6 +1,12 @@
 package auth_test
 
 import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sync/atomic"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -53,3 +59,48 @@ func (d *dummyUaaClient) RevokeToken(token string) error {
 	d.RevokedToken = token
 	return d.Error
 }
+
+type dummyContextUaaClient struct {
+	dummyUaaClient
+	Context context.Context
+}
+
+func (d *dummyContextUaaClient) ClientCredentialGrantContext(ctx context.Context, clientId, clientSecret string) (string, error) {
+	d.Context = ctx
+	return d.ClientCredentialGrant(clientId, clientSecret)
+}
+
+func (d *dummyContextUaaClient) PasswordGrantContext(ctx context.Context, clientId, clientSecret, username, password string) (string, string, error) {
+	d.Context = ctx
+	return d.PasswordGrant(clientId, clientSecret, username, password)
+}
+
+func (d *dummyContextUaaClient) RefreshTokenGrantContext(ctx context.Context, clientId, clientSecret, refreshToken string) (string, string, error) {
+	d.Context = ctx
+	return d.RefreshTokenGrant(clientId, clientSecret, refreshToken)
+}
+
+func (d *dummyContextUaaClient) RevokeTokenContext(ctx context.Context, token string) error {
+	d.Context = ctx
+	return d.RevokeToken(token)
+}
+
+// blockingUaaClient counts refresh token grants, which block until release is closed
+type blockingUaaClient struct {
+	dummyUaaClient
+	grants  atomic.Int32
+	release chan struct{}
+}
+
+func (b *blockingUaaClient) RefreshTokenGrant(clientId, clientSecret, refreshToken string) (string, string, error) {
+	b.grants.Add(1)
+	<-b.release
+	return "new-access-token", "new-refresh-token", nil
+}
+
+// jwtExpiringIn returns an unsigned JWT whose exp claim is d from now
+func jwtExpiringIn(d time.Duration) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"jti":"some-jti","exp":%d}`, time.Now().Add(d).Unix())))
+	return header + "." + payload + ".signature"
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/internal/fileversion"
+)
+
+// TokenRejectedError is returned by BearerTokenStrategy when the server responds with
+// 401 Unauthorized, since a token provided by the caller cannot be refreshed
+type TokenRejectedError struct {
+	// Name and Description are decoded from the response body, when present
+	Name        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *TokenRejectedError) Error() string {
+	switch {
+	case e.Name == "":
+		return "the bearer token was rejected by the server"
+	case e.Description == "":
+		return "the bearer token was rejected by the server: " + e.Name
+	default:
+		return fmt.Sprintf("the bearer token was rejected by the server: %s: %s", e.Name, e.Description)
+	}
+}
+
+// BearerTokenStrategy submits requests with bearer token authorization, using a token provided by the
+// caller or read from a file. The file is read again whenever it changes, so that a token rotated by
+// another process, eg. a sidecar, is picked up.
+//
+// The token is never refreshed. Responses with 401 Unauthorized are returned as a *TokenRejectedError.
+type BearerTokenStrategy struct {
+	ApiClient *http.Client
+
+	token string
+	path  string
+
+	mu      sync.Mutex // guards cached & version
+	cached  string
+	version fileversion.Version
+}
+
+var _ Strategy = new(BearerTokenStrategy)
+
+// Do submits requests with bearer token authorization
+func (s *BearerTokenStrategy) Do(req *http.Request) (*http.Response, error) {
+	token, err := s.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := s.ApiClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	rejected := &TokenRejectedError{}
+	json.NewDecoder(bytes.NewReader(body)).Decode(rejected)
+
+	return nil, rejected
+}
+
+// Token returns the token, reading the token file again if it has changed
+func (s *BearerTokenStrategy) Token() (string, error) {
+	if s.path == "" {
+		return s.token, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	version, err := fileversion.Stat(s.path)
+	if err != nil {
+		return "", err
+	}
+
+	if s.cached != "" && version == s.version {
+		return s.cached, nil
+	}
+
+	token, err := readTokenFile(s.path)
+	if err != nil {
+		return "", err
+	}
+
+	s.cached = token
+	s.version = version
+
+	return s.cached, nil
+}
+
+func readTokenFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", errors.New("the token file " + path + " is empty")
+	}
+
+	return token, nil
+}
@@ -0,0 +1,142 @@
+package auth_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type dummyConfig struct {
+	client *http.Client
+}
+
+func (d *dummyConfig) AuthURL() (string, error) {
+	return "", errors.New("no auth server")
+}
+
+func (d *dummyConfig) Client() *http.Client {
+	return d.client
+}
+
+var _ = Describe("BearerTokenStrategy", func() {
+	var (
+		apiServer  *httptest.Server
+		authTokens []string
+		status     int
+		config     *dummyConfig
+	)
+
+	BeforeEach(func() {
+		authTokens = nil
+		status = http.StatusOK
+
+		apiServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authTokens = append(authTokens, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+			w.WriteHeader(status)
+			if status == http.StatusUnauthorized {
+				w.Write([]byte(`{"error": "invalid_token", "error_description": "The token has been revoked"}`))
+			}
+		}))
+		DeferCleanup(apiServer.Close)
+
+		config = &dummyConfig{client: apiServer.Client()}
+	})
+
+	get := func(strategy auth.Strategy) (*http.Response, error) {
+		req, _ := http.NewRequest(http.MethodGet, apiServer.URL+"/api/v1/data", nil)
+		return strategy.Do(req)
+	}
+
+	Describe("BearerToken()", func() {
+		It("submits requests with the token", func() {
+			strategy, err := auth.BearerToken("some-token")(config)
+			Expect(err).NotTo(HaveOccurred())
+
+			resp, err := get(strategy)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(authTokens).To(Equal([]string{"some-token"}))
+		})
+
+		It("requires a token", func() {
+			_, err := auth.BearerToken("")(config)
+
+			Expect(err).To(MatchError("bearer token authentication requires a token"))
+		})
+
+		It("returns a TokenRejectedError when the server rejects the token", func() {
+			status = http.StatusUnauthorized
+			strategy, _ := auth.BearerToken("some-token")(config)
+
+			_, err := get(strategy)
+
+			var rejected *auth.TokenRejectedError
+			Expect(errors.As(err, &rejected)).To(BeTrue())
+			Expect(rejected.Name).To(Equal("invalid_token"))
+			Expect(err).To(MatchError("the bearer token was rejected by the server: invalid_token: The token has been revoked"))
+			Expect(authTokens).To(HaveLen(1))
+		})
+	})
+
+	Describe("TokenFile()", func() {
+		var path string
+
+		BeforeEach(func() {
+			path = filepath.Join(GinkgoT().TempDir(), "token")
+			Expect(os.WriteFile(path, []byte("first-token\n"), 0600)).To(Succeed())
+		})
+
+		It("reads the token again when the file changes", func() {
+			strategy, err := auth.TokenFile(path)(config)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = get(strategy)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = get(strategy)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.WriteFile(path, []byte("second-token\n"), 0600)).To(Succeed())
+			later := time.Now().Add(time.Minute)
+			Expect(os.Chtimes(path, later, later)).To(Succeed())
+
+			_, err = get(strategy)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(authTokens).To(Equal([]string{"first-token", "first-token", "second-token"}))
+		})
+
+		It("requires the file to exist", func() {
+			_, err := auth.TokenFile(filepath.Join(filepath.Dir(path), "missing"))(config)
+
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("rejects an empty file", func() {
+			Expect(os.WriteFile(path, []byte("\n"), 0600)).To(Succeed())
+
+			_, err := auth.TokenFile(path)(config)
+
+			Expect(err).To(MatchError("the token file " + path + " is empty"))
+		})
+
+		It("fails requests once the file is removed", func() {
+			strategy, _ := auth.TokenFile(path)(config)
+			Expect(os.Remove(path)).To(Succeed())
+
+			_, err := get(strategy)
+
+			Expect(os.IsNotExist(err)).To(BeTrue())
+			Expect(authTokens).To(BeEmpty())
+		})
+	})
+})
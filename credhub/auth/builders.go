@@ -1,9 +1,13 @@
 package auth
 
 import (
+	"crypto/tls"
+	"errors"
 	"net/http"
 
+	"code.cloudfoundry.org/credhub-cli/credhub/auth/oidc"
 	"code.cloudfoundry.org/credhub-cli/credhub/auth/uaa"
+	"code.cloudfoundry.org/credhub-cli/credhub/metrics"
 )
 
 // Config provides the CredHub configuration necessary to build an auth Strategy
@@ -14,6 +18,22 @@ type Config interface {
 	Client() *http.Client
 }
 
+// MetricsConfig is implemented by Configs that provide a metrics.Recorder to the strategies they build
+//
+// The credhub.CredHub struct conforms to this interface
+type MetricsConfig interface {
+	Config
+	Metrics() metrics.Recorder
+}
+
+// MutualTLSConfig is implemented by Configs that present a client certificate to the server
+//
+// The credhub.CredHub struct conforms to this interface
+type MutualTLSConfig interface {
+	Config
+	ClientCertificate() *tls.Certificate
+}
+
 // Builder constructs the auth type given a configuration
 //
 // A builder is required by the credhub.Auth() option for credhub.New()
@@ -24,6 +44,58 @@ var Noop Builder = func(config Config) (Strategy, error) {
 	return &NoopStrategy{config.Client()}, nil
 }
 
+// MutualTLS builds a MutualTLSStrategy
+//
+// The client certificate is provided by the credhub.ClientCert() option, which is required.
+var MutualTLS Builder = func(config Config) (Strategy, error) {
+	if mtlsConfig, ok := config.(MutualTLSConfig); ok && mtlsConfig.ClientCertificate() == nil {
+		return nil, errors.New("mutual TLS authentication requires a client certificate")
+	}
+
+	return &MutualTLSStrategy{config.Client()}, nil
+}
+
+// BearerToken builds a BearerTokenStrategy which submits requests with token
+func BearerToken(token string) Builder {
+	return func(config Config) (Strategy, error) {
+		if token == "" {
+			return nil, errors.New("bearer token authentication requires a token")
+		}
+
+		return &BearerTokenStrategy{ApiClient: config.Client(), token: token}, nil
+	}
+}
+
+// TokenFile builds a BearerTokenStrategy which submits requests with the token in the file at path
+//
+// The file must exist when the strategy is built. It is read again whenever it changes.
+func TokenFile(path string) Builder {
+	return func(config Config) (Strategy, error) {
+		strategy := &BearerTokenStrategy{ApiClient: config.Client(), path: path}
+
+		if _, err := strategy.Token(); err != nil {
+			return nil, err
+		}
+
+		return strategy, nil
+	}
+}
+
+// Exec builds an ExecStrategy which runs command with args to obtain access tokens
+func Exec(command string, args ...string) Builder {
+	return func(config Config) (Strategy, error) {
+		if command == "" {
+			return nil, errors.New("the exec credential plugin requires a command")
+		}
+
+		return &ExecStrategy{
+			ApiClient: config.Client(),
+			Command:   command,
+			Args:      args,
+		}, nil
+	}
+}
+
 // UaaPassword builds an OauthStrategy for UAA using password_grant token requests
 func UaaPassword(clientId, clientSecret, username, password string) Builder {
 	return Uaa(clientId, clientSecret, username, password, "", "", false)
@@ -49,18 +121,69 @@ func Uaa(clientId, clientSecret, username, password, accessToken, refreshToken s
 			Client:  httpClient,
 		}
 
-		oauth := &OAuthStrategy{
-			Username:                username,
-			Password:                password,
-			ClientId:                clientId,
-			ClientSecret:            clientSecret,
-			ApiClient:               httpClient,
-			OAuthClient:             &uaaClient,
-			ClientCredentialRefresh: usingClientCrendentials,
+		return newOAuthStrategy(config, &uaaClient, clientId, clientSecret, username, password, accessToken, refreshToken, usingClientCrendentials), nil
+	}
+}
+
+// OIDCPassword builds an OauthStrategy for an OpenID Connect provider using password grant token requests
+func OIDCPassword(clientId, clientSecret, username, password string) Builder {
+	return OIDC(clientId, clientSecret, username, password, "", "", false)
+}
+
+// OIDCClientCredentials builds an OauthStrategy for an OpenID Connect provider using client_credentials grant token requests
+func OIDCClientCredentials(clientId, clientSecret string) Builder {
+	return OIDC(clientId, clientSecret, "", "", "", "", true)
+}
+
+// OIDC builds an OauthStrategy for an OpenID Connect provider using existing tokens
+//
+// The auth server URL, which is fetched from /info unless provided with the credhub.AuthURL()
+// option, is the provider's issuer. Its endpoints are discovered from the issuer's
+// .well-known/openid-configuration document.
+func OIDC(clientId, clientSecret, username, password, accessToken, refreshToken string, usingClientCrendentials bool) Builder {
+	return func(config Config) (Strategy, error) {
+		httpClient := config.Client()
+		issuer, err := config.AuthURL()
+
+		if err != nil {
+			return nil, err
 		}
 
-		oauth.SetTokens(accessToken, refreshToken)
+		oidcClient := oidc.Client{
+			Issuer:       issuer,
+			Client:       httpClient,
+			ClientId:     clientId,
+			ClientSecret: clientSecret,
+		}
+
+		return newOAuthStrategy(config, &oidcClient, clientId, clientSecret, username, password, accessToken, refreshToken, usingClientCrendentials), nil
+	}
+}
+
+func newOAuthStrategy(config Config, oauthClient OAuthClient, clientId, clientSecret, username, password, accessToken, refreshToken string, usingClientCrendentials bool) *OAuthStrategy {
+	oauth := &OAuthStrategy{
+		Username:                username,
+		Password:                password,
+		ClientId:                clientId,
+		ClientSecret:            clientSecret,
+		ApiClient:               config.Client(),
+		OAuthClient:             oauthClient,
+		ClientCredentialRefresh: usingClientCrendentials,
+	}
 
-		return oauth, nil
+	if metricsConfig, ok := config.(MetricsConfig); ok {
+		oauth.Metrics = metricsConfig.Metrics()
 	}
+
+	oauth.SetTokens(accessToken, refreshToken)
+
+	return oauth
 }
+
+var (
+	_ OAuthContextClient = new(uaa.Client)
+	_ OAuthContextClient = new(oidc.Client)
+
+	_ AuthorizationCodeClient = new(uaa.Client)
+	_ AuthorizationCodeClient = new(oidc.Client)
+)
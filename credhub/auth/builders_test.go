@@ -1,9 +1,11 @@
 package auth
 
 import (
+	"crypto/tls"
 	"errors"
 	"net/http"
 
+	"code.cloudfoundry.org/credhub-cli/credhub/auth/oidc"
 	"code.cloudfoundry.org/credhub-cli/credhub/auth/uaa"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -21,7 +23,55 @@ func (d *DummyServerConfig) Client() *http.Client {
 	return http.DefaultClient
 }
 
+type DummyMutualTLSConfig struct {
+	DummyServerConfig
+	Certificate *tls.Certificate
+}
+
+func (d *DummyMutualTLSConfig) ClientCertificate() *tls.Certificate {
+	return d.Certificate
+}
+
 var _ = Describe("Constructors", func() {
+	Describe("MutualTLS", func() {
+		It("constructs a MutualTLSStrategy using the config's client", func() {
+			config := DummyMutualTLSConfig{Certificate: &tls.Certificate{}}
+			strategy, err := MutualTLS(&config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strategy.(*MutualTLSStrategy).Client).To(BeIdenticalTo(config.Client()))
+		})
+
+		Context("when the config has no client certificate", func() {
+			It("returns an error", func() {
+				config := DummyMutualTLSConfig{}
+				_, err := MutualTLS(&config)
+
+				Expect(err).To(MatchError("mutual TLS authentication requires a client certificate"))
+			})
+		})
+	})
+
+	Describe("Exec()", func() {
+		It("builds an ExecStrategy", func() {
+			config := &DummyServerConfig{}
+
+			strategy, err := Exec("credhub-token", "--audience", "credhub")(config)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strategy).To(Equal(&ExecStrategy{
+				ApiClient: config.Client(),
+				Command:   "credhub-token",
+				Args:      []string{"--audience", "credhub"},
+			}))
+		})
+
+		It("requires a command", func() {
+			_, err := Exec("")(&DummyServerConfig{})
+
+			Expect(err).To(MatchError("the exec credential plugin requires a command"))
+		})
+	})
+
 	Describe("PasswordGrant()", func() {
 		It("constructs a OAuthStrategy auth using password grant", func() {
 			config := DummyServerConfig{}
@@ -82,6 +132,38 @@ var _ = Describe("Constructors", func() {
 		})
 	})
 
+	Describe("OIDC()", func() {
+		It("constructs a OAuthStrategy for the OpenID Connect provider at the auth URL", func() {
+			config := DummyServerConfig{}
+			builder := OIDC("some-client-id", "some-client-secret", "", "", "some-access-token", "some-refresh-token", true)
+			strategy, err := builder(&config)
+			Expect(err).NotTo(HaveOccurred())
+
+			auth := strategy.(*OAuthStrategy)
+			Expect(auth.ClientId).To(Equal("some-client-id"))
+			Expect(auth.ClientCredentialRefresh).To(BeTrue())
+			Expect(auth.AccessToken()).To(Equal("some-access-token"))
+			Expect(auth.RefreshToken()).To(Equal("some-refresh-token"))
+
+			oidcClient := auth.OAuthClient.(*oidc.Client)
+			Expect(oidcClient.Issuer).To(Equal("http://example.com/auth/url"))
+			Expect(oidcClient.ClientId).To(Equal("some-client-id"))
+			Expect(oidcClient.ClientSecret).To(Equal("some-client-secret"))
+			Expect(oidcClient.Client).To(BeIdenticalTo(config.Client()))
+		})
+
+		Context("when fetching an Auth URL fails", func() {
+			It("returns an error", func() {
+				config := DummyServerConfig{
+					Error: errors.New("Failed to fetch Auth URL"),
+				}
+				_, err := OIDCClientCredentials("some-client-id", "some-client-secret")(&config)
+
+				Expect(err).To(MatchError("Failed to fetch Auth URL"))
+			})
+		})
+	})
+
 	Describe("AuthBuilder()", func() {
 		It("constructs a OAuthStrategy auth using existing tokens", func() {
 			config := DummyServerConfig{}
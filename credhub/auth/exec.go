@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// ExecCredential is the JSON object printed on stdout by an exec credential plugin, eg.
+//
+//	{"access_token": "eyJhbGciOi...", "expires_at": "2024-01-02T15:04:05Z"}
+type ExecCredential struct {
+	// AccessToken is submitted as the bearer token of requests
+	AccessToken string `json:"access_token"`
+
+	// ExpiresAt is when the AccessToken expires, in RFC 3339 format. When omitted, the exp claim
+	// of the AccessToken is used instead, and tokens without one are used until the server
+	// reports they have expired.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// ExecStrategy submits requests with bearer token authorization, using access tokens printed by an external command.
+//
+// The command is run before the first request, unless a token was cached with SetToken(), and again when the
+// access token is about to expire or the server reports it has expired. Its stderr is passed through, so that
+// it may prompt the user.
+type ExecStrategy struct {
+	ApiClient *http.Client
+
+	// Command and Args are the command which prints an ExecCredential
+	Command string
+	Args    []string
+
+	// Env holds environment variables added to the environment of the command, in the form key=value
+	Env []string
+
+	// RefreshSkew is how long before the expiry of the access token the command is run again.
+	// Defaults to DefaultRefreshSkew.
+	RefreshSkew time.Duration
+
+	// OnToken, when set, is called with every access token printed by the command and its expiry, which is
+	// zero when unknown, eg. to save them for SetToken() in a later process
+	OnToken func(accessToken string, expiry time.Time)
+
+	mu          sync.Mutex // guards accessToken & expiry, and is held while the command runs
+	accessToken string
+	expiry      time.Time
+}
+
+var _ Strategy = new(ExecStrategy)
+
+// Do submits requests with bearer token authorization, using the access token printed by the command
+func (s *ExecStrategy) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	accessToken, err := s.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var clone *http.Request
+	if req.Body != nil && req.GetBody == nil {
+		clone, err = cloneRequest(req)
+
+		if err != nil {
+			return nil, errors.New("failed to clone request body: " + err.Error())
+		}
+	}
+
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	resp, err := s.ApiClient.Do(req)
+
+	if err != nil {
+		return resp, err
+	}
+
+	expired, err := tokenExpired(resp)
+
+	if err != nil || !expired {
+		return resp, err
+	}
+
+	resp.Body.Close()
+	s.invalidate(accessToken)
+
+	accessToken, err = s.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	retry, err := rewindRequest(req, clone)
+
+	if err != nil {
+		return nil, errors.New("failed to clone request body: " + err.Error())
+	}
+
+	retry.Header.Set("Authorization", "Bearer "+accessToken)
+	return s.ApiClient.Do(retry)
+}
+
+// Token returns the cached access token, running the command to obtain a new one when there is none
+// or it is about to expire. Concurrent callers share a single run of the command.
+func (s *ExecStrategy) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken != "" && (s.expiry.IsZero() || time.Until(s.expiry) > s.refreshSkew()) {
+		return s.accessToken, nil
+	}
+
+	credential, err := s.run(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	s.accessToken = credential.AccessToken
+	s.expiry = time.Time{}
+	if credential.ExpiresAt != nil {
+		s.expiry = *credential.ExpiresAt
+	} else if exp, ok := tokenExpiry(credential.AccessToken); ok {
+		s.expiry = exp
+	}
+
+	if s.OnToken != nil {
+		s.OnToken(s.accessToken, s.expiry)
+	}
+
+	return s.accessToken, nil
+}
+
+// SetToken caches an access token printed by an earlier run of the command, which is used instead of running
+// the command until it is about to expire. A zero expiry is used until the server reports the token has expired.
+func (s *ExecStrategy) SetToken(accessToken string, expiry time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accessToken = accessToken
+	s.expiry = expiry
+}
+
+func (s *ExecStrategy) run(ctx context.Context) (*ExecCredential, error) {
+	var stdout bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, s.Command, s.Args...)
+	cmd.Env = append(os.Environ(), s.Env...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("the exec credential plugin %q failed: %w", s.Command, err)
+	}
+
+	var credential ExecCredential
+	if err := json.Unmarshal(stdout.Bytes(), &credential); err != nil {
+		return nil, fmt.Errorf("the exec credential plugin %q printed an invalid credential: %w", s.Command, err)
+	}
+
+	if credential.AccessToken == "" {
+		return nil, fmt.Errorf("the exec credential plugin %q did not print an access token", s.Command)
+	}
+
+	return &credential, nil
+}
+
+// invalidate discards accessToken, unless another request has already replaced it
+func (s *ExecStrategy) invalidate(accessToken string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken == accessToken {
+		s.accessToken = ""
+		s.expiry = time.Time{}
+	}
+}
+
+func (s *ExecStrategy) refreshSkew() time.Duration {
+	if s.RefreshSkew <= 0 {
+		return DefaultRefreshSkew
+	}
+	return s.RefreshSkew
+}
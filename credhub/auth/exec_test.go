@@ -0,0 +1,201 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ExecStrategy", func() {
+	var (
+		dir        string
+		plugin     string
+		runs       string
+		apiServer  *httptest.Server
+		authTokens []string
+		expired    map[string]bool
+		strategy   *auth.ExecStrategy
+	)
+
+	// writePlugin writes a plugin which records each run and prints output
+	writePlugin := func(output string, exitCode int) {
+		script := "#!/bin/sh\n" +
+			"echo run >> '" + runs + "'\n" +
+			"cat <<EOF\n" + output + "\nEOF\n" +
+			"exit " + strconv.Itoa(exitCode) + "\n"
+		Expect(os.WriteFile(plugin, []byte(script), 0700)).To(Succeed())
+	}
+
+	runCount := func() int {
+		data, err := os.ReadFile(runs)
+		if os.IsNotExist(err) {
+			return 0
+		}
+		Expect(err).NotTo(HaveOccurred())
+		return strings.Count(string(data), "run")
+	}
+
+	BeforeEach(func() {
+		if runtime.GOOS == "windows" {
+			Skip("the test plugin is a shell script")
+		}
+
+		dir = GinkgoT().TempDir()
+		plugin = filepath.Join(dir, "plugin")
+		runs = filepath.Join(dir, "runs")
+		authTokens = nil
+		expired = map[string]bool{}
+
+		apiServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			authTokens = append(authTokens, token)
+			if expired[token] {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error": "access_token_expired"}`))
+				return
+			}
+			w.Write([]byte(`{}`))
+		}))
+		DeferCleanup(apiServer.Close)
+
+		strategy = &auth.ExecStrategy{
+			ApiClient: apiServer.Client(),
+			Command:   plugin,
+			Args:      []string{"--audience", "credhub"},
+		}
+	})
+
+	It("runs the command once and reuses the access token until it expires", func() {
+		writePlugin(`{"access_token": "some-token", "expires_at": "`+time.Now().Add(time.Hour).Format(time.RFC3339)+`"}`, 0)
+
+		for i := 0; i < 3; i++ {
+			req, _ := http.NewRequest(http.MethodGet, apiServer.URL+"/api/v1/data", nil)
+			resp, err := strategy.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		}
+
+		Expect(runCount()).To(Equal(1))
+		Expect(authTokens).To(Equal([]string{"some-token", "some-token", "some-token"}))
+	})
+
+	It("runs the command again when the access token is about to expire", func() {
+		writePlugin(`{"access_token": "some-token", "expires_at": "`+time.Now().Add(10*time.Second).Format(time.RFC3339)+`"}`, 0)
+
+		for i := 0; i < 2; i++ {
+			req, _ := http.NewRequest(http.MethodGet, apiServer.URL+"/api/v1/data", nil)
+			_, err := strategy.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(runCount()).To(Equal(2))
+	})
+
+	It("uses the exp claim when the credential has no expiry", func() {
+		writePlugin(`{"access_token": "`+jwtExpiringIn(10*time.Second)+`"}`, 0)
+
+		for i := 0; i < 2; i++ {
+			req, _ := http.NewRequest(http.MethodGet, apiServer.URL+"/api/v1/data", nil)
+			_, err := strategy.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(runCount()).To(Equal(2))
+	})
+
+	It("runs the command again and retries the request when the server reports the token has expired", func() {
+		writePlugin(`{"access_token": "some-token"}`, 0)
+		expired["some-token"] = true
+
+		req, _ := http.NewRequest(http.MethodPut, apiServer.URL+"/api/v1/data", strings.NewReader(`{"name":"some-name"}`))
+		resp, err := strategy.Do(req)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		Expect(runCount()).To(Equal(2))
+		Expect(authTokens).To(Equal([]string{"some-token", "some-token"}))
+	})
+
+	It("uses a token cached with SetToken until it is about to expire", func() {
+		writePlugin(`{"access_token": "new-token"}`, 0)
+		strategy.SetToken("cached-token", time.Now().Add(time.Hour))
+
+		req, _ := http.NewRequest(http.MethodGet, apiServer.URL+"/api/v1/data", nil)
+		_, err := strategy.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(runCount()).To(Equal(0))
+
+		strategy.SetToken("cached-token", time.Now().Add(10*time.Second))
+
+		req, _ = http.NewRequest(http.MethodGet, apiServer.URL+"/api/v1/data", nil)
+		_, err = strategy.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(runCount()).To(Equal(1))
+		Expect(authTokens).To(Equal([]string{"cached-token", "new-token"}))
+	})
+
+	It("passes every token printed by the command to OnToken", func() {
+		expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+		writePlugin(`{"access_token": "some-token", "expires_at": "`+expiresAt.Format(time.RFC3339)+`"}`, 0)
+
+		var tokens []string
+		var expiries []time.Time
+		strategy.OnToken = func(accessToken string, expiry time.Time) {
+			tokens = append(tokens, accessToken)
+			expiries = append(expiries, expiry)
+		}
+
+		_, err := strategy.Token(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		_, err = strategy.Token(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(tokens).To(Equal([]string{"some-token"}))
+		Expect(expiries).To(HaveLen(1))
+		Expect(expiries[0]).To(BeTemporally("==", expiresAt))
+	})
+
+	It("passes the arguments and environment to the command", func() {
+		script := "#!/bin/sh\nprintf '{\"access_token\": \"%s-%s-%s\"}' \"$1\" \"$2\" \"$CREDHUB_AUDIENCE\"\n"
+		Expect(os.WriteFile(plugin, []byte(script), 0700)).To(Succeed())
+		strategy.Env = []string{"CREDHUB_AUDIENCE=prod"}
+
+		token, err := strategy.Token(context.Background())
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(Equal("--audience-credhub-prod"))
+	})
+
+	It("returns an error when the command fails", func() {
+		writePlugin(`{"access_token": "some-token"}`, 1)
+
+		req, _ := http.NewRequest(http.MethodGet, apiServer.URL+"/api/v1/data", nil)
+		_, err := strategy.Do(req)
+
+		Expect(err).To(MatchError(ContainSubstring("the exec credential plugin %q failed: exit status 1", plugin)))
+		Expect(authTokens).To(BeEmpty())
+	})
+
+	It("returns an error when the command prints an invalid credential", func() {
+		writePlugin(`not json`, 0)
+
+		_, err := strategy.Token(context.Background())
+		Expect(err).To(MatchError(ContainSubstring("printed an invalid credential")))
+
+		writePlugin(`{"expires_at": "2030-01-02T15:04:05Z"}`, 0)
+
+		_, err = strategy.Token(context.Background())
+		Expect(err).To(MatchError(ContainSubstring("did not print an access token")))
+	})
+})
@@ -0,0 +1,24 @@
+// Authorization code requests with PKCE (RFC 7636), shared by the UAA and OpenID Connect clients
+package pkce
+
+import "net/url"
+
+// AuthorizationURL returns the URL of the authorization endpoint which starts an authorization code grant,
+// with the S256 PKCE codeChallenge. The query of endpoint is kept.
+func AuthorizationURL(endpoint, clientId, redirectURI, state, codeChallenge string) (string, error) {
+	authorizeURL, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	query := authorizeURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", clientId)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("state", state)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authorizeURL.RawQuery = query.Encode()
+
+	return authorizeURL.String(), nil
+}
@@ -0,0 +1,13 @@
+package auth
+
+import "net/http"
+
+// MutualTLSStrategy will submit requests authenticated by the client certificate
+// presented during the TLS handshake, without a bearer token.
+//
+// CredHub identifies such requests by the certificate's app GUID, eg. mtls-app:<guid>.
+type MutualTLSStrategy struct {
+	*http.Client
+}
+
+var _ Strategy = new(MutualTLSStrategy)
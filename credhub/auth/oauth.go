@@ -2,6 +2,8 @@ package auth
 
 import (
 	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,6 +11,9 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/metrics"
 )
 
 // OAuth authentication strategy
@@ -16,7 +21,8 @@ type OAuthStrategy struct {
 	accessToken  string
 	refreshToken string
 
-	mu sync.RWMutex // guards AccessToken & Refresh Token
+	mu         sync.RWMutex // guards AccessToken, RefreshToken & refreshing
+	refreshing *tokenRequest
 
 	Username                string
 	Password                string
@@ -25,6 +31,26 @@ type OAuthStrategy struct {
 	ApiClient               *http.Client
 	OAuthClient             OAuthClient
 	ClientCredentialRefresh bool
+
+	// Metrics receives the outcome of every Refresh. Defaults to metrics.Noop
+	Metrics metrics.Recorder
+
+	// RefreshSkew is how long before the expiry of the AccessToken Do() refreshes it.
+	// Defaults to DefaultRefreshSkew. A negative value disables proactive refreshes, so
+	// that the token is only refreshed once the server reports it has expired.
+	RefreshSkew time.Duration
+}
+
+// DefaultRefreshSkew is the default RefreshSkew of an OAuthStrategy
+const DefaultRefreshSkew = 30 * time.Second
+
+// tokenRequest is a token grant shared by concurrent callers of Refresh() or Login()
+type tokenRequest struct {
+	done chan struct{}
+	err  error
+
+	// abandoned is set when the context of the caller which made the grant ended before the grant completed
+	abandoned bool
 }
 
 type OAuthClient interface {
@@ -34,23 +60,60 @@ type OAuthClient interface {
 	RevokeToken(token string) error
 }
 
+// OAuthContextClient is an OAuthClient whose requests can be cancelled through a context.
+//
+// When the OAuthClient of an OAuthStrategy implements this interface, token grants made
+// on behalf of a request are bound to that request's context. uaa.Client implements it.
+type OAuthContextClient interface {
+	OAuthClient
+	ClientCredentialGrantContext(ctx context.Context, clientId, clientSecret string) (string, error)
+	PasswordGrantContext(ctx context.Context, clientId, clientSecret, username, password string) (string, string, error)
+	RefreshTokenGrantContext(ctx context.Context, clientId, clientSecret, refreshToken string) (string, string, error)
+	RevokeTokenContext(ctx context.Context, token string) error
+}
+
+// AuthorizationCodeClient is an OAuthClient which supports the authorization code grant with PKCE (RFC 7636),
+// used to log in through a browser. uaa.Client and oidc.Client implement it.
+type AuthorizationCodeClient interface {
+	OAuthClient
+	AuthorizationURL(clientId, redirectURI, state, codeChallenge string) (string, error)
+	AuthorizationCodeGrant(clientId, clientSecret, code, redirectURI, codeVerifier string) (string, string, error)
+}
+
 // Do submits requests with bearer token authorization, using the AccessToken as the bearer token.
 //
-// Will automatically refresh the AccessToken and retry the request if the token has expired.
+// Will automatically refresh the AccessToken shortly before it expires, according to its exp claim,
+// and will refresh it and retry the request if the server reports the token has expired.
+// Token grants are bound to the request's context.
 func (a *OAuthStrategy) Do(req *http.Request) (*http.Response, error) {
-	if err := a.Login(); err != nil {
+	ctx := req.Context()
+
+	if err := a.LoginContext(ctx); err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+a.AccessToken())
+	if skew := a.refreshSkew(); a.tokenExpiresWithin(skew) {
+		// another request may refresh the token first, in which case it is not refreshed again
+		err := a.refreshIf(ctx, func() bool { return expiresWithin(a.accessToken, skew) })
 
-	clone, err := cloneRequest(req)
+		// keep using the current token if it is still valid, the server has the final say
+		if err != nil && a.tokenExpiresWithin(0) {
+			return nil, err
+		}
+	}
 
-	if err != nil {
-		return nil, errors.New("failed to clone request body: " + err.Error())
+	var clone *http.Request
+	if req.Body != nil && req.GetBody == nil {
+		var err error
+		clone, err = cloneRequest(req)
+
+		if err != nil {
+			return nil, errors.New("failed to clone request body: " + err.Error())
+		}
 	}
 
-	req.Header.Set("Authorization", "Bearer "+a.AccessToken())
+	accessToken := a.AccessToken()
+	req.Header.Set("Authorization", "Bearer "+accessToken)
 	resp, err := a.ApiClient.Do(req)
 
 	if err != nil {
@@ -63,12 +126,21 @@ func (a *OAuthStrategy) Do(req *http.Request) (*http.Response, error) {
 		return resp, err
 	}
 
-	if err := a.Refresh(); err != nil {
+	resp.Body.Close()
+
+	// another request may have refreshed the token while this one was in flight
+	if err := a.refreshIf(ctx, func() bool { return a.accessToken == accessToken }); err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+a.AccessToken())
-	return a.ApiClient.Do(clone)
+	retry, err := rewindRequest(req, clone)
+
+	if err != nil {
+		return nil, errors.New("failed to clone request body: " + err.Error())
+	}
+
+	retry.Header.Set("Authorization", "Bearer "+a.AccessToken())
+	return a.ApiClient.Do(retry)
 }
 
 // Refresh will get a new AccessToken
@@ -76,19 +148,47 @@ func (a *OAuthStrategy) Do(req *http.Request) (*http.Response, error) {
 // If RefreshToken is available, a refresh token grant will be used, otherwise
 // client credential grant will be used.
 func (a *OAuthStrategy) Refresh() error {
+	return a.RefreshContext(context.Background())
+}
+
+// RefreshContext is like Refresh, but the token grant is bound to ctx
+//
+// Concurrent calls share a single token grant, which is bound to the context of the first caller. When that
+// context ends first, the other callers make a grant of their own.
+func (a *OAuthStrategy) RefreshContext(ctx context.Context) error {
+	return a.refreshIf(ctx, nil)
+}
+
+// refreshIf refreshes the AccessToken if needed reports that it is still needed once no other token grant is in flight
+func (a *OAuthStrategy) refreshIf(ctx context.Context, needed func() bool) error {
+	return a.singleFlight(ctx, needed, func(ctx context.Context) error {
+		start := time.Now()
+		err := a.refresh(ctx)
+
+		outcome := metrics.RefreshSuccess
+		if err != nil {
+			outcome = metrics.RefreshFailure
+		}
+		a.metrics().ObserveRefresh(outcome, time.Since(start))
+
+		return err
+	})
+}
+
+func (a *OAuthStrategy) refresh(ctx context.Context) error {
 	refreshToken := a.RefreshToken()
 
 	if refreshToken == "" {
-		return a.requestToken()
+		return a.requestToken(ctx)
 	}
 
 	var accessToken string
 	var err error
 
 	if a.ClientCredentialRefresh {
-		accessToken, err = a.OAuthClient.ClientCredentialGrant(a.ClientId, a.ClientSecret)
+		accessToken, err = a.clientCredentialGrant(ctx)
 	} else {
-		accessToken, refreshToken, err = a.OAuthClient.RefreshTokenGrant(a.ClientId, a.ClientSecret, refreshToken)
+		accessToken, refreshToken, err = a.refreshTokenGrant(ctx, refreshToken)
 	}
 
 	if err != nil {
@@ -107,13 +207,18 @@ func (a *OAuthStrategy) Refresh() error {
 //
 // On success, the AccessToken and RefreshToken will be empty
 func (a *OAuthStrategy) Logout() error {
+	return a.LogoutContext(context.Background())
+}
+
+// LogoutContext is like Logout, but the revoke token request is bound to ctx
+func (a *OAuthStrategy) LogoutContext(ctx context.Context) error {
 	accessToken := a.AccessToken()
 
 	if accessToken == "" {
 		return nil
 	}
 
-	if err := a.OAuthClient.RevokeToken(a.AccessToken()); err != nil {
+	if err := a.revokeToken(ctx, accessToken); err != nil {
 		return err
 	}
 
@@ -131,22 +236,76 @@ func (a *OAuthStrategy) Logout() error {
 //
 // Login will be a no-op if the AccessToken is not empty when invoked.
 func (a *OAuthStrategy) Login() error {
+	return a.LoginContext(context.Background())
+}
+
+// LoginContext is like Login, but the token grant is bound to ctx
+//
+// Concurrent calls share a single token grant, which is bound to the context of the first caller. When that
+// context ends first, the other callers make a grant of their own.
+func (a *OAuthStrategy) LoginContext(ctx context.Context) error {
 	if a.AccessToken() != "" && a.AccessToken() != "revoked" {
 		return nil
 	}
 
-	return a.requestToken()
+	return a.singleFlight(ctx, func() bool { return a.accessToken == "" || a.accessToken == "revoked" }, a.requestToken)
 }
 
-func (a *OAuthStrategy) requestToken() error {
+// singleFlight calls grant, unless a token grant is already in flight, in which case it waits for that grant's result.
+//
+// needed, when not nil, is checked with the lock held before a grant is started, so that a caller which decided to
+// request a token just before another caller's grant completed does not start a second one. It must read the
+// tokens from the fields rather than through AccessToken() and RefreshToken().
+func (a *OAuthStrategy) singleFlight(ctx context.Context, needed func() bool, grant func(context.Context) error) error {
+	for {
+		a.mu.Lock()
+
+		inFlight := a.refreshing
+		if inFlight == nil {
+			break
+		}
+		a.mu.Unlock()
+
+		select {
+		case <-inFlight.done:
+			// a grant which failed because its caller gave up says nothing about this caller's grant
+			if !inFlight.abandoned || ctx.Err() != nil {
+				return inFlight.err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if needed != nil && !needed() {
+		a.mu.Unlock()
+		return nil
+	}
+
+	request := &tokenRequest{done: make(chan struct{})}
+	a.refreshing = request
+	a.mu.Unlock()
+
+	request.err = grant(ctx)
+	request.abandoned = request.err != nil && ctx.Err() != nil
+
+	a.mu.Lock()
+	a.refreshing = nil
+	a.mu.Unlock()
+	close(request.done)
+
+	return request.err
+}
+
+func (a *OAuthStrategy) requestToken(ctx context.Context) error {
 	var accessToken string
 	var refreshToken string
 	var err error
 
 	if a.ClientCredentialRefresh {
-		accessToken, err = a.OAuthClient.ClientCredentialGrant(a.ClientId, a.ClientSecret)
+		accessToken, err = a.clientCredentialGrant(ctx)
 	} else {
-		accessToken, refreshToken, err = a.OAuthClient.PasswordGrant(a.ClientId, a.ClientSecret, a.Username, a.Password)
+		accessToken, refreshToken, err = a.passwordGrant(ctx)
 	}
 
 	if err != nil {
@@ -184,6 +343,63 @@ func (a *OAuthStrategy) SetTokens(access, refresh string) {
 	a.refreshToken = refresh
 }
 
+func (a *OAuthStrategy) refreshSkew() time.Duration {
+	if a.RefreshSkew == 0 {
+		return DefaultRefreshSkew
+	}
+	return a.RefreshSkew
+}
+
+// tokenExpiresWithin reports whether the AccessToken expires within d. Tokens without
+// an exp claim, and negative durations, are never considered to expire.
+func (a *OAuthStrategy) tokenExpiresWithin(d time.Duration) bool {
+	return expiresWithin(a.AccessToken(), d)
+}
+
+func expiresWithin(accessToken string, d time.Duration) bool {
+	if d < 0 {
+		return false
+	}
+
+	expiry, ok := tokenExpiry(accessToken)
+	return ok && time.Until(expiry) <= d
+}
+
+func (a *OAuthStrategy) metrics() metrics.Recorder {
+	if a.Metrics != nil {
+		return a.Metrics
+	}
+	return metrics.Noop
+}
+
+func (a *OAuthStrategy) clientCredentialGrant(ctx context.Context) (string, error) {
+	if client, ok := a.OAuthClient.(OAuthContextClient); ok {
+		return client.ClientCredentialGrantContext(ctx, a.ClientId, a.ClientSecret)
+	}
+	return a.OAuthClient.ClientCredentialGrant(a.ClientId, a.ClientSecret)
+}
+
+func (a *OAuthStrategy) passwordGrant(ctx context.Context) (string, string, error) {
+	if client, ok := a.OAuthClient.(OAuthContextClient); ok {
+		return client.PasswordGrantContext(ctx, a.ClientId, a.ClientSecret, a.Username, a.Password)
+	}
+	return a.OAuthClient.PasswordGrant(a.ClientId, a.ClientSecret, a.Username, a.Password)
+}
+
+func (a *OAuthStrategy) refreshTokenGrant(ctx context.Context, refreshToken string) (string, string, error) {
+	if client, ok := a.OAuthClient.(OAuthContextClient); ok {
+		return client.RefreshTokenGrantContext(ctx, a.ClientId, a.ClientSecret, refreshToken)
+	}
+	return a.OAuthClient.RefreshTokenGrant(a.ClientId, a.ClientSecret, refreshToken)
+}
+
+func (a *OAuthStrategy) revokeToken(ctx context.Context, token string) error {
+	if client, ok := a.OAuthClient.(OAuthContextClient); ok {
+		return client.RevokeTokenContext(ctx, token)
+	}
+	return a.OAuthClient.RevokeToken(token)
+}
+
 func tokenExpired(resp *http.Response) (bool, error) {
 	if resp.StatusCode < 400 {
 		return false, nil
@@ -210,6 +426,59 @@ func tokenExpired(resp *http.Response) (bool, error) {
 	return errResp["error"] == "access_token_expired", nil
 }
 
+// tokenExpiry decodes the exp claim of a JWT, without verifying its signature
+func tokenExpiry(token string) (time.Time, bool) {
+	segments := strings.Split(token, ".")
+
+	if len(segments) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segments[1], "="))
+
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Exp *json.Number `json:"exp"`
+	}
+
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == nil {
+		return time.Time{}, false
+	}
+
+	exp, err := claims.Exp.Float64()
+
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(exp), 0), true
+}
+
+// rewindRequest returns a copy of req whose body can be sent again, using the clone
+// made before req was sent if its body could not be recreated through GetBody
+func rewindRequest(req *http.Request, clone *http.Request) (*http.Request, error) {
+	if clone != nil {
+		return clone, nil
+	}
+
+	retry := req.Clone(req.Context())
+
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+
+		if err != nil {
+			return nil, err
+		}
+
+		retry.Body = body
+	}
+
+	return retry, nil
+}
+
 func cloneRequest(r *http.Request) (*http.Request, error) {
 	if r.Body == nil {
 		return r, nil
@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OAuthStrategy refreshIf", func() {
+	It("does not make a second grant once another caller has refreshed the token", func() {
+		client := &countingOAuthClient{accessToken: unsignedJWT(time.Hour)}
+		uaa := OAuthStrategy{OAuthClient: client}
+		uaa.SetTokens(unsignedJWT(time.Second), "old-refresh-token")
+		expiring := func() bool { return expiresWithin(uaa.accessToken, DefaultRefreshSkew) }
+
+		// both callers found the token about to expire, the first one refreshes it before the second gets the lock
+		Expect(uaa.refreshIf(context.Background(), expiring)).To(Succeed())
+		Expect(uaa.refreshIf(context.Background(), expiring)).To(Succeed())
+
+		Expect(client.grants).To(Equal(1))
+		Expect(uaa.AccessToken()).To(Equal(client.accessToken))
+	})
+
+	It("always makes a grant when nothing is checked", func() {
+		client := &countingOAuthClient{accessToken: unsignedJWT(time.Hour)}
+		uaa := OAuthStrategy{OAuthClient: client}
+		uaa.SetTokens(unsignedJWT(time.Hour), "old-refresh-token")
+
+		Expect(uaa.RefreshContext(context.Background())).To(Succeed())
+
+		Expect(client.grants).To(Equal(1))
+	})
+})
+
+var _ = Describe("OAuthStrategy singleFlight", func() {
+	It("makes another grant for waiting callers when the context of the first caller ends", func() {
+		client := &cancellableOAuthClient{countingOAuthClient: countingOAuthClient{accessToken: "new-access-token"}, started: make(chan struct{}, 2)}
+		uaa := OAuthStrategy{OAuthClient: client}
+		uaa.SetTokens("old-access-token", "old-refresh-token")
+
+		leaderCtx, cancel := context.WithCancel(context.Background())
+		leaderErr := make(chan error, 1)
+		go func() {
+			leaderErr <- uaa.RefreshContext(leaderCtx)
+		}()
+		Eventually(client.started).Should(Receive())
+
+		waiterErr := make(chan error, 1)
+		go func() {
+			waiterErr <- uaa.RefreshContext(context.Background())
+		}()
+		Consistently(waiterErr, 100*time.Millisecond).ShouldNot(Receive())
+
+		cancel()
+
+		Eventually(leaderErr).Should(Receive(MatchError(context.Canceled)))
+		Eventually(waiterErr).Should(Receive(BeNil()))
+		Expect(client.grants).To(Equal(2))
+		Expect(uaa.AccessToken()).To(Equal("new-access-token"))
+	})
+})
+
+// cancellableOAuthClient blocks its first refresh token grant until the context of the grant ends
+type cancellableOAuthClient struct {
+	countingOAuthClient
+	started chan struct{}
+}
+
+func (c *cancellableOAuthClient) ClientCredentialGrantContext(ctx context.Context, clientId, clientSecret string) (string, error) {
+	return c.ClientCredentialGrant(clientId, clientSecret)
+}
+
+func (c *cancellableOAuthClient) PasswordGrantContext(ctx context.Context, clientId, clientSecret, username, password string) (string, string, error) {
+	return c.PasswordGrant(clientId, clientSecret, username, password)
+}
+
+func (c *cancellableOAuthClient) RefreshTokenGrantContext(ctx context.Context, clientId, clientSecret, refreshToken string) (string, string, error) {
+	c.started <- struct{}{}
+	if c.grants == 0 {
+		c.grants++
+		<-ctx.Done()
+		return "", "", ctx.Err()
+	}
+	return c.RefreshTokenGrant(clientId, clientSecret, refreshToken)
+}
+
+func (c *cancellableOAuthClient) RevokeTokenContext(ctx context.Context, token string) error {
+	return c.RevokeToken(token)
+}
+
+type countingOAuthClient struct {
+	accessToken string
+	grants      int
+}
+
+func (c *countingOAuthClient) ClientCredentialGrant(clientId, clientSecret string) (string, error) {
+	c.grants++
+	return c.accessToken, nil
+}
+
+func (c *countingOAuthClient) PasswordGrant(clientId, clientSecret, username, password string) (string, string, error) {
+	c.grants++
+	return c.accessToken, "new-refresh-token", nil
+}
+
+func (c *countingOAuthClient) RefreshTokenGrant(clientId, clientSecret, refreshToken string) (string, string, error) {
+	c.grants++
+	return c.accessToken, "new-refresh-token", nil
+}
+
+func (c *countingOAuthClient) RevokeToken(token string) error {
+	return nil
+}
+
+func unsignedJWT(expiresIn time.Duration) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, time.Now().Add(expiresIn).Unix())))
+	return header + "." + payload + ".signature"
+}
@@ -1,13 +1,17 @@
 package auth_test
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
+	"time"
 
 	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+	"code.cloudfoundry.org/credhub-cli/credhub/metrics"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -157,6 +161,39 @@ var _ = Describe("OAuthStrategy", func() {
 
 		})
 
+		Context("when the OAuth client supports contexts", func() {
+			type ctxKey struct{}
+
+			It("binds the token grant to the request context", func() {
+				contextUaaClient := &dummyContextUaaClient{}
+				contextUaaClient.NewAccessToken = "new-access-token"
+
+				apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				}))
+
+				defer apiServer.Close()
+
+				oauth := auth.OAuthStrategy{
+					OAuthClient:             contextUaaClient,
+					ApiClient:               http.DefaultClient,
+					ClientId:                "client-id",
+					ClientSecret:            "client-secret",
+					ClientCredentialRefresh: true,
+				}
+
+				ctx := context.WithValue(context.Background(), ctxKey{}, "some-value")
+				request, _ := http.NewRequestWithContext(ctx, "GET", apiServer.URL, nil)
+
+				_, err := oauth.Do(request)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(contextUaaClient.Context).ToNot(BeNil())
+				Expect(contextUaaClient.Context.Value(ctxKey{})).To(Equal("some-value"))
+				Expect(oauth.AccessToken()).To(Equal("new-access-token"))
+			})
+		})
+
 		Context("when the access token has expired", func() {
 			It("should refresh the token and submit the request again", func() {
 				apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -245,6 +282,167 @@ var _ = Describe("OAuthStrategy", func() {
 			})
 		})
 
+		Context("when the access token is about to expire", func() {
+			var (
+				apiServer   *httptest.Server
+				authHeaders []string
+			)
+
+			BeforeEach(func() {
+				authHeaders = nil
+				apiServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					body, _ := io.ReadAll(r.Body)
+					authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+					w.Write(body)
+				}))
+
+				mockUaaClient.NewAccessToken = "new-access-token"
+				mockUaaClient.NewRefreshToken = "new-refresh-token"
+			})
+
+			AfterEach(func() {
+				apiServer.Close()
+			})
+
+			It("refreshes the token before submitting the request", func() {
+				uaa := auth.OAuthStrategy{
+					ApiClient:   http.DefaultClient,
+					OAuthClient: mockUaaClient,
+				}
+				uaa.SetTokens(jwtExpiringIn(10*time.Second), "old-refresh-token")
+
+				request, _ := http.NewRequest("POST", apiServer.URL, strings.NewReader("some body"))
+				response, err := uaa.Do(request)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(mockUaaClient.RefreshToken).To(Equal("old-refresh-token"))
+				Expect(authHeaders).To(Equal([]string{"Bearer new-access-token"}))
+
+				body, _ := io.ReadAll(response.Body)
+				Expect(string(body)).To(Equal("some body"))
+			})
+
+			It("honours the configured RefreshSkew", func() {
+				uaa := auth.OAuthStrategy{
+					ApiClient:   http.DefaultClient,
+					OAuthClient: mockUaaClient,
+					RefreshSkew: time.Second,
+				}
+				token := jwtExpiringIn(10 * time.Second)
+				uaa.SetTokens(token, "old-refresh-token")
+
+				request, _ := http.NewRequest("GET", apiServer.URL, nil)
+				_, err := uaa.Do(request)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(mockUaaClient.RefreshToken).To(BeEmpty())
+				Expect(authHeaders).To(Equal([]string{"Bearer " + token}))
+			})
+
+			It("does not refresh the token when RefreshSkew is negative", func() {
+				uaa := auth.OAuthStrategy{
+					ApiClient:   http.DefaultClient,
+					OAuthClient: mockUaaClient,
+					RefreshSkew: -1,
+				}
+				uaa.SetTokens(jwtExpiringIn(-time.Minute), "old-refresh-token")
+
+				request, _ := http.NewRequest("GET", apiServer.URL, nil)
+				_, err := uaa.Do(request)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(mockUaaClient.RefreshToken).To(BeEmpty())
+			})
+
+			It("does not refresh tokens without an exp claim", func() {
+				uaa := auth.OAuthStrategy{
+					ApiClient:   http.DefaultClient,
+					OAuthClient: mockUaaClient,
+				}
+				uaa.SetTokens("some-opaque-token", "old-refresh-token")
+
+				request, _ := http.NewRequest("GET", apiServer.URL, nil)
+				_, err := uaa.Do(request)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(mockUaaClient.RefreshToken).To(BeEmpty())
+				Expect(authHeaders).To(Equal([]string{"Bearer some-opaque-token"}))
+			})
+
+			Context("when refreshing the token fails", func() {
+				BeforeEach(func() {
+					mockUaaClient.Error = errors.New("failed to refresh")
+				})
+
+				It("submits the request with the current token while it is valid", func() {
+					uaa := auth.OAuthStrategy{
+						ApiClient:   http.DefaultClient,
+						OAuthClient: mockUaaClient,
+					}
+					token := jwtExpiringIn(10 * time.Second)
+					uaa.SetTokens(token, "old-refresh-token")
+
+					request, _ := http.NewRequest("GET", apiServer.URL, nil)
+					_, err := uaa.Do(request)
+
+					Expect(err).ToNot(HaveOccurred())
+					Expect(authHeaders).To(Equal([]string{"Bearer " + token}))
+				})
+
+				It("returns an error once the current token has expired", func() {
+					uaa := auth.OAuthStrategy{
+						ApiClient:   http.DefaultClient,
+						OAuthClient: mockUaaClient,
+					}
+					uaa.SetTokens(jwtExpiringIn(-time.Minute), "old-refresh-token")
+
+					request, _ := http.NewRequest("GET", apiServer.URL, nil)
+					_, err := uaa.Do(request)
+
+					Expect(err).To(MatchError("failed to refresh"))
+					Expect(authHeaders).To(BeEmpty())
+				})
+			})
+		})
+
+		Context("when concurrent requests find the access token about to expire", func() {
+			It("makes a single refresh grant", func() {
+				apiServer := fixedResponseServer(http.StatusOK, []byte("success"))
+				defer apiServer.Close()
+
+				slowUaaClient := &blockingUaaClient{release: make(chan struct{})}
+				uaa := auth.OAuthStrategy{
+					ApiClient:   http.DefaultClient,
+					OAuthClient: slowUaaClient,
+				}
+				uaa.SetTokens(jwtExpiringIn(time.Second), "old-refresh-token")
+
+				var wg sync.WaitGroup
+				errs := make(chan error, 10)
+				for i := 0; i < 10; i++ {
+					wg.Add(1)
+					go func() {
+						defer GinkgoRecover()
+						defer wg.Done()
+						request, _ := http.NewRequest("GET", apiServer.URL, nil)
+						_, err := uaa.Do(request)
+						errs <- err
+					}()
+				}
+
+				Eventually(slowUaaClient.grants.Load).Should(BeEquivalentTo(1))
+				close(slowUaaClient.release)
+				wg.Wait()
+				close(errs)
+
+				for err := range errs {
+					Expect(err).ToNot(HaveOccurred())
+				}
+				Expect(slowUaaClient.grants.Load()).To(BeEquivalentTo(1))
+				Expect(uaa.AccessToken()).To(Equal("new-access-token"))
+			})
+		})
+
 		Context("when cloning the request fails", func() {
 			It("returns an error", func() {
 				uaa := auth.OAuthStrategy{}
@@ -405,6 +603,27 @@ var _ = Describe("OAuthStrategy", func() {
 
 			})
 		})
+
+		Context("with a metrics recorder", func() {
+			It("reports the outcome of each refresh", func() {
+				recorder := metrics.NewInMemory()
+				uaa := auth.OAuthStrategy{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					OAuthClient:  mockUaaClient,
+					Metrics:      recorder,
+				}
+
+				uaa.SetTokens("", "some-refresh-token")
+				Expect(uaa.Refresh()).To(Succeed())
+
+				mockUaaClient.Error = errors.New("refresh token grant failed")
+				Expect(uaa.Refresh()).NotTo(Succeed())
+
+				Expect(recorder.RefreshCount(metrics.RefreshSuccess)).To(Equal(1))
+				Expect(recorder.RefreshCount(metrics.RefreshFailure)).To(Equal(1))
+			})
+		})
 	})
 
 	Context("Login()", func() {
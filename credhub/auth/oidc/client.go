@@ -0,0 +1,328 @@
+// OpenID Connect client for token grants and revocation
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/auth/internal/pkce"
+)
+
+// ErrRevocationNotSupported is returned by RevokeToken when the provider does not advertise a revocation endpoint
+var ErrRevocationNotSupported = errors.New("the OpenID Connect provider does not support token revocation")
+
+// Client makes requests to the OpenID Connect provider identified by Issuer.
+//
+// The provider's endpoints are discovered from Issuer/.well-known/openid-configuration
+// on first use. Clients authenticate to the token and revocation endpoints with HTTP
+// Basic authentication (client_secret_basic), unless the provider only supports
+// client_secret_post. Public clients, without a secret, only send their client_id.
+type Client struct {
+	Issuer string
+	Client *http.Client
+
+	// ClientId and ClientSecret authenticate token revocation requests, which
+	// unlike token grants are not given the client credentials by the caller
+	ClientId     string
+	ClientSecret string
+
+	mu       sync.Mutex // guards metadata
+	metadata *ProviderMetadata
+}
+
+// ProviderMetadata captures the fields of the OpenID Connect discovery document used by Client.
+// See: https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+type ProviderMetadata struct {
+	Issuer                                 string   `json:"issuer"`
+	AuthorizationEndpoint                  string   `json:"authorization_endpoint"`
+	TokenEndpoint                          string   `json:"token_endpoint"`
+	RevocationEndpoint                     string   `json:"revocation_endpoint"`
+	GrantTypesSupported                    []string `json:"grant_types_supported"`
+	TokenEndpointAuthMethodsSupported      []string `json:"token_endpoint_auth_methods_supported"`
+	RevocationEndpointAuthMethodsSupported []string `json:"revocation_endpoint_auth_methods_supported"`
+}
+
+type token struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+}
+
+type responseError struct {
+	Name        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *responseError) Error() string {
+	if e.Description == "" {
+		return e.Name
+	}
+
+	return fmt.Sprintf("%s %s", e.Name, e.Description)
+}
+
+// Discover fetches the provider metadata from Issuer/.well-known/openid-configuration.
+//
+// The metadata is cached after the first successful request.
+func (c *Client) Discover() (*ProviderMetadata, error) {
+	return c.DiscoverContext(context.Background())
+}
+
+// DiscoverContext is like Discover, but the request is bound to ctx
+func (c *Client) DiscoverContext(ctx context.Context) (*ProviderMetadata, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.metadata != nil {
+		return c.metadata, nil
+	}
+
+	issuer := strings.TrimSuffix(c.Issuer, "/")
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Add("Accept", "application/json")
+	response, err := c.Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	defer io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch OpenID Connect provider metadata: received HTTP %d", response.StatusCode)
+	}
+
+	var metadata ProviderMetadata
+	if err := json.NewDecoder(response.Body).Decode(&metadata); err != nil {
+		return nil, err
+	}
+
+	// the issuer must match exactly to prevent impersonation, see OpenID Connect Discovery 1.0 section 4.3
+	if strings.TrimSuffix(metadata.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("the OpenID Connect provider metadata is for issuer %q instead of %q", metadata.Issuer, c.Issuer)
+	}
+
+	if metadata.TokenEndpoint == "" {
+		return nil, errors.New("the OpenID Connect provider metadata does not include a token endpoint")
+	}
+
+	c.metadata = &metadata
+
+	return c.metadata, nil
+}
+
+// ClientCredentialGrant requests a token using client_credentials grant type
+func (c *Client) ClientCredentialGrant(clientId, clientSecret string) (string, error) {
+	return c.ClientCredentialGrantContext(context.Background(), clientId, clientSecret)
+}
+
+// ClientCredentialGrantContext is like ClientCredentialGrant, but the token request is bound to ctx
+func (c *Client) ClientCredentialGrantContext(ctx context.Context, clientId, clientSecret string) (string, error) {
+	values := url.Values{
+		"grant_type": {"client_credentials"},
+	}
+
+	token, err := c.tokenGrantRequest(ctx, clientId, clientSecret, values)
+
+	return token.AccessToken, err
+}
+
+// PasswordGrant requests an access token and refresh token using password grant type
+func (c *Client) PasswordGrant(clientId, clientSecret, username, password string) (string, string, error) {
+	return c.PasswordGrantContext(context.Background(), clientId, clientSecret, username, password)
+}
+
+// PasswordGrantContext is like PasswordGrant, but the token request is bound to ctx
+func (c *Client) PasswordGrantContext(ctx context.Context, clientId, clientSecret, username, password string) (string, string, error) {
+	values := url.Values{
+		"grant_type": {"password"},
+		"username":   {username},
+		"password":   {password},
+	}
+
+	token, err := c.tokenGrantRequest(ctx, clientId, clientSecret, values)
+
+	return token.AccessToken, token.RefreshToken, err
+}
+
+// AuthorizationURL returns the URL of the provider's authorization endpoint which starts an authorization code grant.
+//
+// The user agent is redirected to redirectURI with the authorization code and state once the user
+// has logged in. codeChallenge is the S256 PKCE challenge (RFC 7636) of the verifier which is later
+// passed to AuthorizationCodeGrant.
+func (c *Client) AuthorizationURL(clientId, redirectURI, state, codeChallenge string) (string, error) {
+	return c.AuthorizationURLContext(context.Background(), clientId, redirectURI, state, codeChallenge)
+}
+
+// AuthorizationURLContext is like AuthorizationURL, but the discovery request is bound to ctx
+func (c *Client) AuthorizationURLContext(ctx context.Context, clientId, redirectURI, state, codeChallenge string) (string, error) {
+	metadata, err := c.DiscoverContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if metadata.AuthorizationEndpoint == "" {
+		return "", errors.New("the OpenID Connect provider metadata does not include an authorization endpoint")
+	}
+
+	return pkce.AuthorizationURL(metadata.AuthorizationEndpoint, clientId, redirectURI, state, codeChallenge)
+}
+
+// AuthorizationCodeGrant requests an access token and refresh token using authorization_code grant type
+func (c *Client) AuthorizationCodeGrant(clientId, clientSecret, code, redirectURI, codeVerifier string) (string, string, error) {
+	return c.AuthorizationCodeGrantContext(context.Background(), clientId, clientSecret, code, redirectURI, codeVerifier)
+}
+
+// AuthorizationCodeGrantContext is like AuthorizationCodeGrant, but the token request is bound to ctx
+func (c *Client) AuthorizationCodeGrantContext(ctx context.Context, clientId, clientSecret, code, redirectURI, codeVerifier string) (string, string, error) {
+	values := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {codeVerifier},
+	}
+
+	token, err := c.tokenGrantRequest(ctx, clientId, clientSecret, values)
+
+	return token.AccessToken, token.RefreshToken, err
+}
+
+// RefreshTokenGrant requests a new access token and refresh token using refresh_token grant type
+//
+// Providers which do not rotate refresh tokens omit the refresh token from the response,
+// in which case the given refresh token is returned.
+func (c *Client) RefreshTokenGrant(clientId, clientSecret, refreshToken string) (string, string, error) {
+	return c.RefreshTokenGrantContext(context.Background(), clientId, clientSecret, refreshToken)
+}
+
+// RefreshTokenGrantContext is like RefreshTokenGrant, but the token request is bound to ctx
+func (c *Client) RefreshTokenGrantContext(ctx context.Context, clientId, clientSecret, refreshToken string) (string, string, error) {
+	values := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	}
+
+	token, err := c.tokenGrantRequest(ctx, clientId, clientSecret, values)
+	if err != nil {
+		return "", "", err
+	}
+
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+
+	return token.AccessToken, token.RefreshToken, nil
+}
+
+// RevokeToken revokes the given access token as described by RFC 7009
+func (c *Client) RevokeToken(accessToken string) error {
+	return c.RevokeTokenContext(context.Background(), accessToken)
+}
+
+// RevokeTokenContext is like RevokeToken, but the revocation request is bound to ctx
+func (c *Client) RevokeTokenContext(ctx context.Context, accessToken string) error {
+	metadata, err := c.DiscoverContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if metadata.RevocationEndpoint == "" {
+		return ErrRevocationNotSupported
+	}
+
+	values := url.Values{
+		"token":           {accessToken},
+		"token_type_hint": {"access_token"},
+	}
+
+	response, err := c.postForm(ctx, metadata.RevocationEndpoint, metadata.RevocationEndpointAuthMethodsSupported, c.ClientId, c.ClientSecret, values)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusBadRequest {
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("Received HTTP %d error while revoking token from auth server: %q", response.StatusCode, body)
+	}
+
+	return nil
+}
+
+func (c *Client) tokenGrantRequest(ctx context.Context, clientId, clientSecret string, values url.Values) (token, error) {
+	var t token
+
+	metadata, err := c.DiscoverContext(ctx)
+	if err != nil {
+		return t, err
+	}
+
+	response, err := c.postForm(ctx, metadata.TokenEndpoint, metadata.TokenEndpointAuthMethodsSupported, clientId, clientSecret, values)
+	if err != nil {
+		return t, err
+	}
+	defer response.Body.Close()
+	defer io.Copy(io.Discard, response.Body)
+
+	decoder := json.NewDecoder(response.Body)
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		err = decoder.Decode(&t)
+		return t, err
+	}
+
+	respErr := responseError{}
+
+	if err := decoder.Decode(&respErr); err != nil {
+		return t, fmt.Errorf("Received HTTP %d error while requesting token from auth server", response.StatusCode)
+	}
+
+	return t, &respErr
+}
+
+// postForm sends values to endpoint, authenticated with the client credentials in the way the endpoint supports
+func (c *Client) postForm(ctx context.Context, endpoint string, authMethods []string, clientId, clientSecret string, values url.Values) (*http.Response, error) {
+	form := url.Values{}
+	for key, value := range values {
+		form[key] = value
+	}
+
+	useBasicAuth := clientSecret != "" && (len(authMethods) == 0 || slices.Contains(authMethods, "client_secret_basic"))
+
+	if !useBasicAuth {
+		form.Set("client_id", clientId)
+		if clientSecret != "" {
+			form.Set("client_secret", clientSecret)
+		}
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Add("Accept", "application/json")
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	if useBasicAuth {
+		// client credentials are form encoded before basic encoding, see RFC 6749 section 2.3.1
+		request.SetBasicAuth(url.QueryEscape(clientId), url.QueryEscape(clientSecret))
+	}
+
+	return c.Client.Do(request)
+}
@@ -0,0 +1,270 @@
+package oidc_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+
+	. "code.cloudfoundry.org/credhub-cli/credhub/auth/oidc"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		provider       *httptest.Server
+		metadata       map[string]interface{}
+		discoveries    atomic.Int32
+		tokenRequests  []*http.Request
+		tokenForms     []url.Values
+		tokenStatus    int
+		tokenResponse  string
+		revokeRequests []*http.Request
+		revokeForms    []url.Values
+		client         *Client
+	)
+
+	BeforeEach(func() {
+		discoveries.Store(0)
+		tokenRequests = nil
+		tokenForms = nil
+		revokeRequests = nil
+		revokeForms = nil
+		tokenStatus = http.StatusOK
+		tokenResponse = `{"access_token":"access-token","refresh_token":"refresh-token","token_type":"Bearer","expires_in":300}`
+
+		provider = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/realms/credhub/.well-known/openid-configuration":
+				discoveries.Add(1)
+				json.NewEncoder(w).Encode(metadata)
+			case "/realms/credhub/protocol/token":
+				r.ParseForm()
+				tokenRequests = append(tokenRequests, r)
+				tokenForms = append(tokenForms, r.PostForm)
+				w.WriteHeader(tokenStatus)
+				w.Write([]byte(tokenResponse))
+			case "/realms/credhub/protocol/revoke":
+				r.ParseForm()
+				revokeRequests = append(revokeRequests, r)
+				revokeForms = append(revokeForms, r.PostForm)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+
+		issuer := provider.URL + "/realms/credhub"
+		metadata = map[string]interface{}{
+			"issuer":              issuer,
+			"token_endpoint":      issuer + "/protocol/token",
+			"revocation_endpoint": issuer + "/protocol/revoke",
+		}
+
+		client = &Client{
+			Issuer:       issuer,
+			Client:       http.DefaultClient,
+			ClientId:     "client-id",
+			ClientSecret: "client-secret",
+		}
+	})
+
+	AfterEach(func() {
+		provider.Close()
+	})
+
+	Context("Discover()", func() {
+		It("fetches the provider metadata once", func() {
+			md, err := client.Discover()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(md.TokenEndpoint).To(Equal(provider.URL + "/realms/credhub/protocol/token"))
+
+			_, err = client.Discover()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(discoveries.Load()).To(BeEquivalentTo(1))
+		})
+
+		It("rejects metadata for another issuer", func() {
+			metadata["issuer"] = "https://attacker.example.com"
+
+			_, err := client.Discover()
+
+			Expect(err).To(MatchError(ContainSubstring(`the OpenID Connect provider metadata is for issuer "https://attacker.example.com"`)))
+		})
+
+		It("requires a token endpoint", func() {
+			delete(metadata, "token_endpoint")
+
+			_, err := client.Discover()
+
+			Expect(err).To(MatchError("the OpenID Connect provider metadata does not include a token endpoint"))
+		})
+
+		It("returns an error when the issuer has no discovery document", func() {
+			client.Issuer = provider.URL + "/unknown"
+
+			_, err := client.Discover()
+
+			Expect(err).To(MatchError("unable to fetch OpenID Connect provider metadata: received HTTP 404"))
+		})
+	})
+
+	Context("ClientCredentialGrant()", func() {
+		It("authenticates with HTTP basic authentication by default", func() {
+			accessToken, err := client.ClientCredentialGrant("client-id", "client/secret")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(accessToken).To(Equal("access-token"))
+			Expect(tokenForms).To(HaveLen(1))
+			Expect(tokenForms[0]).To(Equal(url.Values{"grant_type": {"client_credentials"}}))
+			Expect(tokenRequests[0].Header.Get("Content-Type")).To(Equal("application/x-www-form-urlencoded"))
+
+			username, password, ok := tokenRequests[0].BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(username).To(Equal("client-id"))
+			Expect(password).To(Equal("client%2Fsecret"))
+		})
+
+		It("sends the client credentials in the form when the provider only supports client_secret_post", func() {
+			metadata["token_endpoint_auth_methods_supported"] = []string{"client_secret_post"}
+
+			_, err := client.ClientCredentialGrant("client-id", "client-secret")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tokenForms[0].Get("client_id")).To(Equal("client-id"))
+			Expect(tokenForms[0].Get("client_secret")).To(Equal("client-secret"))
+			_, _, ok := tokenRequests[0].BasicAuth()
+			Expect(ok).To(BeFalse())
+		})
+
+		It("returns the error reported by the provider", func() {
+			tokenStatus = http.StatusUnauthorized
+			tokenResponse = `{"error":"invalid_client","error_description":"Invalid client credentials"}`
+
+			_, err := client.ClientCredentialGrant("client-id", "wrong-secret")
+
+			Expect(err).To(MatchError("invalid_client Invalid client credentials"))
+		})
+	})
+
+	Context("PasswordGrant()", func() {
+		It("sends the client_id of public clients in the form", func() {
+			accessToken, refreshToken, err := client.PasswordGrant("public-client", "", "some-user", "some-password")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(accessToken).To(Equal("access-token"))
+			Expect(refreshToken).To(Equal("refresh-token"))
+			Expect(tokenForms[0]).To(Equal(url.Values{
+				"grant_type": {"password"},
+				"username":   {"some-user"},
+				"password":   {"some-password"},
+				"client_id":  {"public-client"},
+			}))
+		})
+	})
+
+	Context("AuthorizationURL()", func() {
+		It("links to the discovered authorization endpoint with a PKCE challenge", func() {
+			metadata["authorization_endpoint"] = provider.URL + "/realms/credhub/protocol/auth?kc_idp_hint=corp"
+
+			authorizationURL, err := client.AuthorizationURL("public-client", "http://127.0.0.1:8080/callback", "some-state", "some-challenge")
+
+			Expect(err).NotTo(HaveOccurred())
+			parsed, err := url.Parse(authorizationURL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed.Path).To(Equal("/realms/credhub/protocol/auth"))
+			Expect(parsed.Query()).To(Equal(url.Values{
+				"kc_idp_hint":           {"corp"},
+				"response_type":         {"code"},
+				"client_id":             {"public-client"},
+				"redirect_uri":          {"http://127.0.0.1:8080/callback"},
+				"state":                 {"some-state"},
+				"code_challenge":        {"some-challenge"},
+				"code_challenge_method": {"S256"},
+			}))
+		})
+
+		It("requires an authorization endpoint", func() {
+			_, err := client.AuthorizationURL("public-client", "http://127.0.0.1:8080/callback", "some-state", "some-challenge")
+
+			Expect(err).To(MatchError("the OpenID Connect provider metadata does not include an authorization endpoint"))
+		})
+	})
+
+	Context("AuthorizationCodeGrant()", func() {
+		It("exchanges the code and verifier for tokens", func() {
+			accessToken, refreshToken, err := client.AuthorizationCodeGrant("public-client", "", "some-code", "http://127.0.0.1:8080/callback", "some-verifier")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(accessToken).To(Equal("access-token"))
+			Expect(refreshToken).To(Equal("refresh-token"))
+			Expect(tokenForms[0]).To(Equal(url.Values{
+				"grant_type":    {"authorization_code"},
+				"code":          {"some-code"},
+				"redirect_uri":  {"http://127.0.0.1:8080/callback"},
+				"code_verifier": {"some-verifier"},
+				"client_id":     {"public-client"},
+			}))
+		})
+	})
+
+	Context("RefreshTokenGrant()", func() {
+		It("makes a refresh token grant request", func() {
+			accessToken, refreshToken, err := client.RefreshTokenGrant("client-id", "client-secret", "old-refresh-token")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(accessToken).To(Equal("access-token"))
+			Expect(refreshToken).To(Equal("refresh-token"))
+			Expect(tokenForms[0]).To(Equal(url.Values{
+				"grant_type":    {"refresh_token"},
+				"refresh_token": {"old-refresh-token"},
+			}))
+		})
+
+		It("keeps the refresh token when the provider does not rotate it", func() {
+			tokenResponse = `{"access_token":"access-token","token_type":"Bearer"}`
+
+			_, refreshToken, err := client.RefreshTokenGrant("client-id", "client-secret", "old-refresh-token")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(refreshToken).To(Equal("old-refresh-token"))
+		})
+	})
+
+	Context("RevokeToken()", func() {
+		It("revokes the token at the revocation endpoint", func() {
+			err := client.RevokeToken("access-token")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revokeForms).To(HaveLen(1))
+			Expect(revokeForms[0]).To(Equal(url.Values{
+				"token":           {"access-token"},
+				"token_type_hint": {"access_token"},
+			}))
+
+			username, _, ok := revokeRequests[0].BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(username).To(Equal("client-id"))
+		})
+
+		It("returns ErrRevocationNotSupported when the provider has no revocation endpoint", func() {
+			delete(metadata, "revocation_endpoint")
+
+			err := client.RevokeToken("access-token")
+
+			Expect(err).To(MatchError(ErrRevocationNotSupported))
+		})
+	})
+
+	It("binds requests to the context", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := client.ClientCredentialGrantContext(ctx, "client-id", "client-secret")
+
+		Expect(err).To(MatchError(context.Canceled))
+	})
+})
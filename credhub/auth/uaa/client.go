@@ -3,6 +3,7 @@ package uaa
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -11,6 +12,9 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/auth/internal/pkce"
 )
 
 // Client makes requests to the UAA server at AuthURL
@@ -67,8 +71,14 @@ func (e *responseError) Error() string {
 	return fmt.Sprintf("%s %s", e.Name, e.Description)
 }
 
+// Metadata fetches the UAA server information from GET /info
 func (u *Client) Metadata() (*Metadata, error) {
-	request, err := http.NewRequest("GET", u.AuthURL+"/info", nil)
+	return u.MetadataContext(context.Background())
+}
+
+// MetadataContext is like Metadata, but the request is bound to ctx
+func (u *Client) MetadataContext(ctx context.Context) (*Metadata, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", u.AuthURL+"/info", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -96,6 +106,11 @@ func (u *Client) Metadata() (*Metadata, error) {
 
 // ClientCredentialGrant requests a token using client_credentials grant type
 func (u *Client) ClientCredentialGrant(clientId, clientSecret string) (string, error) {
+	return u.ClientCredentialGrantContext(context.Background(), clientId, clientSecret)
+}
+
+// ClientCredentialGrantContext is like ClientCredentialGrant, but the token request is bound to ctx
+func (u *Client) ClientCredentialGrantContext(ctx context.Context, clientId, clientSecret string) (string, error) {
 	values := url.Values{
 		"grant_type":    {"client_credentials"},
 		"response_type": {"token"},
@@ -103,13 +118,18 @@ func (u *Client) ClientCredentialGrant(clientId, clientSecret string) (string, e
 		"client_secret": {clientSecret},
 	}
 
-	token, err := u.tokenGrantRequest(values)
+	token, err := u.tokenGrantRequest(ctx, values)
 
 	return token.AccessToken, err
 }
 
 // PasswordGrant requests an access token and refresh token using password grant type
 func (u *Client) PasswordGrant(clientId, clientSecret, username, password string) (string, string, error) {
+	return u.PasswordGrantContext(context.Background(), clientId, clientSecret, username, password)
+}
+
+// PasswordGrantContext is like PasswordGrant, but the token request is bound to ctx
+func (u *Client) PasswordGrantContext(ctx context.Context, clientId, clientSecret, username, password string) (string, string, error) {
 	values := url.Values{
 		"grant_type":    {"password"},
 		"response_type": {"token"},
@@ -119,13 +139,18 @@ func (u *Client) PasswordGrant(clientId, clientSecret, username, password string
 		"client_secret": {clientSecret},
 	}
 
-	token, err := u.tokenGrantRequest(values)
+	token, err := u.tokenGrantRequest(ctx, values)
 
 	return token.AccessToken, token.RefreshToken, err
 }
 
 // PasscodeGrant requests an access token and refresh token using passcode grant type
 func (u *Client) PasscodeGrant(clientId, clientSecret, passcode string) (string, string, error) {
+	return u.PasscodeGrantContext(context.Background(), clientId, clientSecret, passcode)
+}
+
+// PasscodeGrantContext is like PasscodeGrant, but the token request is bound to ctx
+func (u *Client) PasscodeGrantContext(ctx context.Context, clientId, clientSecret, passcode string) (string, string, error) {
 	values := url.Values{
 		"grant_type":    {"password"},
 		"response_type": {"token"},
@@ -134,13 +159,175 @@ func (u *Client) PasscodeGrant(clientId, clientSecret, passcode string) (string,
 		"client_secret": {clientSecret},
 	}
 
-	token, err := u.tokenGrantRequest(values)
+	token, err := u.tokenGrantRequest(ctx, values)
 
 	return token.AccessToken, token.RefreshToken, err
 }
 
+// AuthorizationURL returns the URL of the UAA login page which starts an authorization code grant.
+//
+// The user agent is redirected to redirectURI with the authorization code and state once the user
+// has logged in. codeChallenge is the S256 PKCE challenge (RFC 7636) of the verifier which is later
+// passed to AuthorizationCodeGrant.
+func (u *Client) AuthorizationURL(clientId, redirectURI, state, codeChallenge string) (string, error) {
+	return pkce.AuthorizationURL(u.AuthURL+"/oauth/authorize", clientId, redirectURI, state, codeChallenge)
+}
+
+// AuthorizationCodeGrant requests an access token and refresh token using authorization_code grant type
+func (u *Client) AuthorizationCodeGrant(clientId, clientSecret, code, redirectURI, codeVerifier string) (string, string, error) {
+	return u.AuthorizationCodeGrantContext(context.Background(), clientId, clientSecret, code, redirectURI, codeVerifier)
+}
+
+// AuthorizationCodeGrantContext is like AuthorizationCodeGrant, but the token request is bound to ctx
+func (u *Client) AuthorizationCodeGrantContext(ctx context.Context, clientId, clientSecret, code, redirectURI, codeVerifier string) (string, string, error) {
+	values := url.Values{
+		"grant_type":    {"authorization_code"},
+		"response_type": {"token"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {codeVerifier},
+		"client_id":     {clientId},
+		"client_secret": {clientSecret},
+	}
+
+	token, err := u.tokenGrantRequest(ctx, values)
+
+	return token.AccessToken, token.RefreshToken, err
+}
+
+// DeviceAuthorization is a pending device authorization grant, see RFC 8628.
+//
+// The user completes the grant by visiting VerificationURI on another device and entering UserCode.
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// ErrDeviceAuthorizationExpired is returned by DeviceAccessTokenGrant when the user does not complete the grant in time
+var ErrDeviceAuthorizationExpired = errors.New("the device authorization expired before the login was completed")
+
+// devicePollingUnit scales the polling intervals of the device authorization grant, which are given in seconds
+var devicePollingUnit = time.Second
+
+// DeviceAuthorization starts a device authorization grant
+func (u *Client) DeviceAuthorization(clientId, clientSecret string) (*DeviceAuthorization, error) {
+	return u.DeviceAuthorizationContext(context.Background(), clientId, clientSecret)
+}
+
+// DeviceAuthorizationContext is like DeviceAuthorization, but the request is bound to ctx
+func (u *Client) DeviceAuthorizationContext(ctx context.Context, clientId, clientSecret string) (*DeviceAuthorization, error) {
+	values := url.Values{
+		"client_id":     {clientId},
+		"client_secret": {clientSecret},
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "POST", u.AuthURL+"/oauth/device_authorization", bytes.NewBufferString(values.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Add("Accept", "application/json")
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := u.Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	defer io.Copy(io.Discard, response.Body)
+
+	decoder := json.NewDecoder(response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		respErr := responseError{}
+		if err := decoder.Decode(&respErr); err != nil {
+			return nil, fmt.Errorf("Received HTTP %d error while requesting device authorization from auth server", response.StatusCode)
+		}
+		return nil, &respErr
+	}
+
+	var authorization DeviceAuthorization
+	if err := decoder.Decode(&authorization); err != nil {
+		return nil, err
+	}
+
+	return &authorization, nil
+}
+
+// DeviceAccessTokenGrant polls for an access token and refresh token until the user completes the device authorization
+//
+// The token endpoint is polled at the interval requested by the server, which is increased by
+// five seconds whenever the server asks to slow down.
+func (u *Client) DeviceAccessTokenGrant(clientId, clientSecret string, authorization *DeviceAuthorization) (string, string, error) {
+	return u.DeviceAccessTokenGrantContext(context.Background(), clientId, clientSecret, authorization)
+}
+
+// DeviceAccessTokenGrantContext is like DeviceAccessTokenGrant, but stops polling when ctx is done
+func (u *Client) DeviceAccessTokenGrantContext(ctx context.Context, clientId, clientSecret string, authorization *DeviceAuthorization) (string, string, error) {
+	values := url.Values{
+		"grant_type":    {"urn:ietf:params:oauth:grant-type:device_code"},
+		"response_type": {"token"},
+		"device_code":   {authorization.DeviceCode},
+		"client_id":     {clientId},
+		"client_secret": {clientSecret},
+	}
+
+	interval := time.Duration(authorization.Interval) * devicePollingUnit
+	if interval <= 0 {
+		interval = 5 * devicePollingUnit
+	}
+
+	var deadline time.Time
+	if authorization.ExpiresIn > 0 {
+		deadline = time.Now().Add(time.Duration(authorization.ExpiresIn) * devicePollingUnit)
+	}
+
+	for {
+		if !deadline.IsZero() && time.Now().Add(interval).After(deadline) {
+			return "", "", ErrDeviceAuthorizationExpired
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", "", ctx.Err()
+		case <-timer.C:
+		}
+
+		token, err := u.tokenGrantRequest(ctx, values)
+		if err == nil {
+			return token.AccessToken, token.RefreshToken, nil
+		}
+
+		var respErr *responseError
+		if !errors.As(err, &respErr) {
+			return "", "", err
+		}
+
+		switch respErr.Name {
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * devicePollingUnit
+		case "expired_token":
+			return "", "", ErrDeviceAuthorizationExpired
+		default:
+			return "", "", err
+		}
+	}
+}
+
 // RefreshTokenGrant requests a new access token and refresh token using refresh_token grant type
 func (u *Client) RefreshTokenGrant(clientId, clientSecret, refreshToken string) (string, string, error) {
+	return u.RefreshTokenGrantContext(context.Background(), clientId, clientSecret, refreshToken)
+}
+
+// RefreshTokenGrantContext is like RefreshTokenGrant, but the token request is bound to ctx
+func (u *Client) RefreshTokenGrantContext(ctx context.Context, clientId, clientSecret, refreshToken string) (string, string, error) {
 	values := url.Values{
 		"grant_type":    {"refresh_token"},
 		"response_type": {"token"},
@@ -149,15 +336,15 @@ func (u *Client) RefreshTokenGrant(clientId, clientSecret, refreshToken string)
 		"refresh_token": {refreshToken},
 	}
 
-	token, err := u.tokenGrantRequest(values)
+	token, err := u.tokenGrantRequest(ctx, values)
 
 	return token.AccessToken, token.RefreshToken, err
 }
 
-func (u *Client) tokenGrantRequest(headers url.Values) (token, error) {
+func (u *Client) tokenGrantRequest(ctx context.Context, headers url.Values) (token, error) {
 	var t token
 
-	request, err := http.NewRequest("POST", u.AuthURL+"/oauth/token", bytes.NewBufferString(headers.Encode()))
+	request, err := http.NewRequestWithContext(ctx, "POST", u.AuthURL+"/oauth/token", bytes.NewBufferString(headers.Encode()))
 	if err != nil {
 		return t, err
 	}
@@ -192,6 +379,11 @@ func (u *Client) tokenGrantRequest(headers url.Values) (token, error) {
 
 // RevokeToken revokes the given access token
 func (u *Client) RevokeToken(accessToken string) error {
+	return u.RevokeTokenContext(context.Background(), accessToken)
+}
+
+// RevokeTokenContext is like RevokeToken, but the revocation request is bound to ctx
+func (u *Client) RevokeTokenContext(ctx context.Context, accessToken string) error {
 	segments := strings.Split(accessToken, ".")
 
 	if len(segments) < 2 {
@@ -212,7 +404,7 @@ func (u *Client) RevokeToken(accessToken string) error {
 		return errors.New("could not parse jti from payload")
 	}
 
-	request, err := http.NewRequest(http.MethodDelete, u.AuthURL+"/oauth/token/revoke/"+jti, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.AuthURL+"/oauth/token/revoke/"+jti, nil)
 	if err != nil {
 		return err
 	}
@@ -1,8 +1,11 @@
 package uaa_test
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"time"
 
 	. "code.cloudfoundry.org/credhub-cli/credhub/auth/uaa"
 
@@ -221,6 +224,209 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Context("AuthorizationURL()", func() {
+		It("links to the login page with a PKCE challenge", func() {
+			client := Client{AuthURL: "https://uaa.example.com"}
+
+			authorizationURL, err := client.AuthorizationURL("some-client-id", "http://127.0.0.1:8080/callback", "some-state", "some-challenge")
+
+			Expect(err).NotTo(HaveOccurred())
+			parsed, err := url.Parse(authorizationURL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed.Host).To(Equal("uaa.example.com"))
+			Expect(parsed.Path).To(Equal("/oauth/authorize"))
+			Expect(parsed.Query()).To(Equal(url.Values{
+				"response_type":         {"code"},
+				"client_id":             {"some-client-id"},
+				"redirect_uri":          {"http://127.0.0.1:8080/callback"},
+				"state":                 {"some-state"},
+				"code_challenge":        {"some-challenge"},
+				"code_challenge_method": {"S256"},
+			}))
+		})
+	})
+
+	Context("AuthorizationCodeGrant()", func() {
+		It("should make an authorization code grant token request", func() {
+			uaaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+
+				Expect(r.Method).To(Equal(http.MethodPost))
+
+				Expect(r.URL.Path).To(Equal("/oauth/token"))
+
+				Expect(r.PostForm.Get("grant_type")).To(Equal("authorization_code"))
+				Expect(r.PostForm.Get("code")).To(Equal("some-code"))
+				Expect(r.PostForm.Get("redirect_uri")).To(Equal("http://127.0.0.1:8080/callback"))
+				Expect(r.PostForm.Get("code_verifier")).To(Equal("some-verifier"))
+
+				Expect(r.PostForm.Get("client_id")).To(Equal("client-id"))
+				Expect(r.PostForm.Get("client_secret")).To(Equal(""))
+
+				w.Write([]byte(`{"access_token": "some-access-token", "refresh_token": "some-refresh-token", "token_type": "bearer"}`))
+			}))
+			defer uaaServer.Close()
+
+			client := Client{
+				AuthURL: uaaServer.URL,
+				Client:  http.DefaultClient,
+			}
+
+			accessToken, refreshToken, err := client.AuthorizationCodeGrant("client-id", "", "some-code", "http://127.0.0.1:8080/callback", "some-verifier")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(accessToken).To(Equal("some-access-token"))
+			Expect(refreshToken).To(Equal("some-refresh-token"))
+		})
+	})
+
+	Context("DeviceAuthorization()", func() {
+		It("starts a device authorization grant", func() {
+			uaaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+
+				Expect(r.Method).To(Equal(http.MethodPost))
+				Expect(r.URL.Path).To(Equal("/oauth/device_authorization"))
+				Expect(r.PostForm.Get("client_id")).To(Equal("client-id"))
+
+				w.Write([]byte(`{
+					"device_code": "some-device-code",
+					"user_code": "WDJB-MJHT",
+					"verification_uri": "https://uaa.example.com/device",
+					"verification_uri_complete": "https://uaa.example.com/device?user_code=WDJB-MJHT",
+					"expires_in": 1800,
+					"interval": 5
+				}`))
+			}))
+			defer uaaServer.Close()
+
+			client := Client{
+				AuthURL: uaaServer.URL,
+				Client:  http.DefaultClient,
+			}
+
+			authorization, err := client.DeviceAuthorization("client-id", "")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*authorization).To(Equal(DeviceAuthorization{
+				DeviceCode:              "some-device-code",
+				UserCode:                "WDJB-MJHT",
+				VerificationURI:         "https://uaa.example.com/device",
+				VerificationURIComplete: "https://uaa.example.com/device?user_code=WDJB-MJHT",
+				ExpiresIn:               1800,
+				Interval:                5,
+			}))
+		})
+
+		It("returns the error reported by the server", func() {
+			uaaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error": "unauthorized_client", "error_description": "device grant not allowed"}`))
+			}))
+			defer uaaServer.Close()
+
+			client := Client{
+				AuthURL: uaaServer.URL,
+				Client:  http.DefaultClient,
+			}
+
+			_, err := client.DeviceAuthorization("client-id", "")
+
+			Expect(err).To(MatchError("unauthorized_client device grant not allowed"))
+		})
+	})
+
+	Context("DeviceAccessTokenGrant()", func() {
+		var (
+			uaaServer *httptest.Server
+			responses []string
+			polls     []time.Time
+			client    Client
+		)
+
+		BeforeEach(func() {
+			DeferCleanup(SetDevicePollingUnit(10 * time.Millisecond))
+
+			responses = nil
+			polls = nil
+			uaaServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+
+				Expect(r.URL.Path).To(Equal("/oauth/token"))
+				Expect(r.PostForm.Get("grant_type")).To(Equal("urn:ietf:params:oauth:grant-type:device_code"))
+				Expect(r.PostForm.Get("device_code")).To(Equal("some-device-code"))
+
+				polls = append(polls, time.Now())
+				if len(polls) <= len(responses) {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"error": "` + responses[len(polls)-1] + `"}`))
+					return
+				}
+				w.Write([]byte(`{"access_token": "some-access-token", "refresh_token": "some-refresh-token", "token_type": "bearer"}`))
+			}))
+			DeferCleanup(uaaServer.Close)
+
+			client = Client{
+				AuthURL: uaaServer.URL,
+				Client:  http.DefaultClient,
+			}
+		})
+
+		It("polls until the user completes the authorization, slowing down when asked to", func() {
+			responses = []string{"authorization_pending", "slow_down", "authorization_pending"}
+			start := time.Now()
+
+			accessToken, refreshToken, err := client.DeviceAccessTokenGrant("client-id", "", &DeviceAuthorization{
+				DeviceCode: "some-device-code",
+				Interval:   1,
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(accessToken).To(Equal("some-access-token"))
+			Expect(refreshToken).To(Equal("some-refresh-token"))
+			Expect(polls).To(HaveLen(4))
+			Expect(polls[0].Sub(start)).To(BeNumerically(">=", 10*time.Millisecond))
+			Expect(polls[3].Sub(polls[2])).To(BeNumerically(">=", 60*time.Millisecond))
+		})
+
+		It("stops polling when the user denies the authorization", func() {
+			responses = []string{"authorization_pending", "access_denied"}
+
+			_, _, err := client.DeviceAccessTokenGrant("client-id", "", &DeviceAuthorization{
+				DeviceCode: "some-device-code",
+				Interval:   1,
+			})
+
+			Expect(err).To(MatchError("access_denied"))
+			Expect(polls).To(HaveLen(2))
+		})
+
+		It("gives up once the device code has expired", func() {
+			responses = []string{"authorization_pending", "authorization_pending", "authorization_pending"}
+
+			_, _, err := client.DeviceAccessTokenGrant("client-id", "", &DeviceAuthorization{
+				DeviceCode: "some-device-code",
+				Interval:   1,
+				ExpiresIn:  2,
+			})
+
+			Expect(err).To(MatchError(ErrDeviceAuthorizationExpired))
+			Expect(polls).To(HaveLen(1))
+		})
+
+		It("stops polling when the context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, _, err := client.DeviceAccessTokenGrantContext(ctx, "client-id", "", &DeviceAuthorization{
+				DeviceCode: "some-device-code",
+			})
+
+			Expect(err).To(MatchError(context.Canceled))
+			Expect(polls).To(BeEmpty())
+		})
+	})
+
 	Context("RefreshTokenGrant()", func() {
 		It("should make a refresh grant token request", func() {
 			uaaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -371,6 +577,54 @@ var _ = Describe("Client", func() {
 		}),
 	)
 
+	DescribeTable("the context is cancelled",
+		func(performAction func(context.Context, *Client) error) {
+			requested := false
+			uaaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requested = true
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			defer uaaServer.Close()
+
+			client := &Client{
+				AuthURL: uaaServer.URL,
+				Client:  http.DefaultClient,
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			err := performAction(ctx, client)
+
+			Expect(err).To(MatchError(context.Canceled))
+			Expect(requested).To(BeFalse())
+		},
+		Entry("client credentials", func(ctx context.Context, c *Client) error {
+			_, err := c.ClientCredentialGrantContext(ctx, "client-id", "client-secret")
+			return err
+		}),
+		Entry("password grant", func(ctx context.Context, c *Client) error {
+			_, _, err := c.PasswordGrantContext(ctx, "some-client-id", "some-client-secret", "username", "password")
+			return err
+		}),
+		Entry("passcode grant", func(ctx context.Context, c *Client) error {
+			_, _, err := c.PasscodeGrantContext(ctx, "some-client-id", "some-client-secret", "passcode")
+			return err
+		}),
+		Entry("refresh token grant", func(ctx context.Context, c *Client) error {
+			_, _, err := c.RefreshTokenGrantContext(ctx, "client-id", "client-secret", "some-refresh-token")
+			return err
+		}),
+		Entry("revoke token", func(ctx context.Context, c *Client) error {
+			return c.RevokeTokenContext(ctx, "e30K.eyJqdGkiOiIxIn0K.e30K") // {}.{"jti":"1"}.{}
+		}),
+		Entry("metadata", func(ctx context.Context, c *Client) error {
+			_, err := c.MetadataContext(ctx)
+			return err
+		}),
+	)
+
 	DescribeTable("response body is invalid",
 		func(performAction func(*Client) error) {
 			uaaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
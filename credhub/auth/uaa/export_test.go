@@ -0,0 +1,12 @@
+package uaa
+
+import "time"
+
+// SetDevicePollingUnit speeds up device authorization polling in tests, and returns a func restoring the default
+func SetDevicePollingUnit(unit time.Duration) func() {
+	previous := devicePollingUnit
+	devicePollingUnit = unit
+	return func() {
+		devicePollingUnit = previous
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/url"
 
 	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/values"
 )
 
 func (ch *CredHub) GetAllCertificatesMetadata() ([]credentials.CertificateMetadata, error) {
@@ -54,3 +55,117 @@ func (ch *CredHub) makeGetCertificatesRequest(query url.Values) ([]credentials.C
 
 	return data, nil
 }
+
+// RegenerateCertificate generates a new version of the certificate with the given certificate ID, using the
+// parameters of its latest version. When setAsTransitional is true, the new version of a CA is marked as
+// transitional, so that it is distributed to clients while the current version keeps signing certificates.
+func (ch *CredHub) RegenerateCertificate(certificateID string, setAsTransitional bool) (credentials.Certificate, error) {
+	var cred credentials.Certificate
+
+	requestBody := map[string]interface{}{"set_as_transitional": setAsTransitional}
+	resp, err := ch.Request(http.MethodPost, "/api/v1/certificates/"+url.PathEscape(certificateID)+"/regenerate", nil, requestBody, true)
+	if err != nil {
+		return cred, err
+	}
+
+	defer resp.Body.Close()
+	defer io.Copy(io.Discard, resp.Body)
+
+	dec := json.NewDecoder(resp.Body)
+	err = dec.Decode(&cred)
+
+	return cred, err
+}
+
+// UpdateTransitionalVersion marks the version with the given version ID of the certificate with the given
+// certificate ID as transitional. An empty versionID removes the transitional flag from all of its versions.
+// It returns the active versions of the certificate.
+func (ch *CredHub) UpdateTransitionalVersion(certificateID, versionID string) ([]credentials.Certificate, error) {
+	requestBody := map[string]interface{}{"version": nil}
+	if versionID != "" {
+		requestBody["version"] = versionID
+	}
+
+	resp, err := ch.Request(http.MethodPut, "/api/v1/certificates/"+url.PathEscape(certificateID)+"/update_transitional_version", nil, requestBody, true)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	defer io.Copy(io.Discard, resp.Body)
+
+	var versions []credentials.Certificate
+	dec := json.NewDecoder(resp.Body)
+	err = dec.Decode(&versions)
+
+	return versions, err
+}
+
+// GetCertificateVersions returns the versions of the certificate with the given certificate ID, newest first.
+// When current is true, only its active versions are returned: its latest version and its transitional version.
+func (ch *CredHub) GetCertificateVersions(certificateID string, current bool) ([]credentials.CertificateVersion, error) {
+	query := url.Values{}
+	if current {
+		query.Set("current", "true")
+	}
+
+	resp, err := ch.Request(http.MethodGet, certificateVersionsPath(certificateID), query, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	defer io.Copy(io.Discard, resp.Body)
+
+	var versions []credentials.CertificateVersion
+	dec := json.NewDecoder(resp.Body)
+	err = dec.Decode(&versions)
+
+	return versions, err
+}
+
+// CreateCertificateVersion adds a version with the given value to the certificate with the given certificate
+// ID, such as a CA-signed certificate to be distributed as a transitional version
+func (ch *CredHub) CreateCertificateVersion(certificateID string, value values.Certificate, transitional bool) (credentials.CertificateVersion, error) {
+	var cred credentials.CertificateVersion
+
+	requestBody := map[string]interface{}{
+		"value":        value,
+		"transitional": transitional,
+	}
+	resp, err := ch.Request(http.MethodPost, certificateVersionsPath(certificateID), nil, requestBody, true)
+	if err != nil {
+		return cred, err
+	}
+
+	defer resp.Body.Close()
+	defer io.Copy(io.Discard, resp.Body)
+
+	dec := json.NewDecoder(resp.Body)
+	err = dec.Decode(&cred)
+
+	return cred, err
+}
+
+// DeleteCertificateVersion deletes the version with the given version ID of the certificate with the given
+// certificate ID, and returns the deleted version
+func (ch *CredHub) DeleteCertificateVersion(certificateID, versionID string) (credentials.CertificateVersion, error) {
+	var cred credentials.CertificateVersion
+
+	resp, err := ch.Request(http.MethodDelete, certificateVersionsPath(certificateID)+"/"+url.PathEscape(versionID), nil, nil, true)
+	if err != nil {
+		return cred, err
+	}
+
+	defer resp.Body.Close()
+	defer io.Copy(io.Discard, resp.Body)
+
+	dec := json.NewDecoder(resp.Body)
+	err = dec.Decode(&cred)
+
+	return cred, err
+}
+
+func certificateVersionsPath(certificateID string) string {
+	return "/api/v1/certificates/" + url.PathEscape(certificateID) + "/versions"
+}
@@ -2,6 +2,7 @@ package credhub_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
 	"net/http"
 
@@ -10,6 +11,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	. "code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/values"
 )
 
 var _ = Describe("Certificates", func() {
@@ -161,4 +163,170 @@ var _ = Describe("Certificates", func() {
 			})
 		})
 	})
+
+	Context("regenerating a certificate by ID", func() {
+		It("requests to regenerate the certificate as a transitional version", func() {
+			dummy := &DummyAuth{Response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString("")),
+			}}
+
+			ch, _ := New("https://example.com", Auth(dummy.Builder()))
+			_, _ = ch.RegenerateCertificate("some-id", true)
+			Expect(dummy.Request.URL.String()).To(Equal("https://example.com/api/v1/certificates/some-id/regenerate"))
+			Expect(dummy.Request.Method).To(Equal(http.MethodPost))
+
+			var requestBody map[string]interface{}
+			body, _ := io.ReadAll(dummy.Request.Body)
+			json.Unmarshal(body, &requestBody)
+			Expect(requestBody).To(Equal(map[string]interface{}{"set_as_transitional": true}))
+		})
+
+		It("returns the new version", func() {
+			responseString := `{
+	  "id": "some-version-id",
+	  "name": "/some-ca",
+	  "type": "certificate",
+	  "value": {"ca": "some-ca", "certificate": "some-certificate", "private_key": "some-private-key"},
+	  "version_created_at": "2017-01-05T01:01:01Z"
+	}`
+			dummy := &DummyAuth{Response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString(responseString)),
+			}}
+
+			ch, _ := New("https://example.com", Auth(dummy.Builder()))
+			cred, err := ch.RegenerateCertificate("some-id", true)
+			Expect(err).To(BeNil())
+			Expect(cred.Id).To(Equal("some-version-id"))
+			Expect(cred.Name).To(Equal("/some-ca"))
+			Expect(cred.Value.Certificate).To(Equal("some-certificate"))
+		})
+	})
+
+	Context("updating the transitional version", func() {
+		It("requests to mark the version as transitional", func() {
+			dummy := &DummyAuth{Response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString("")),
+			}}
+
+			ch, _ := New("https://example.com", Auth(dummy.Builder()))
+			_, _ = ch.UpdateTransitionalVersion("some-id", "some-version-id")
+			Expect(dummy.Request.URL.String()).To(Equal("https://example.com/api/v1/certificates/some-id/update_transitional_version"))
+			Expect(dummy.Request.Method).To(Equal(http.MethodPut))
+
+			var requestBody map[string]interface{}
+			body, _ := io.ReadAll(dummy.Request.Body)
+			json.Unmarshal(body, &requestBody)
+			Expect(requestBody).To(Equal(map[string]interface{}{"version": "some-version-id"}))
+		})
+
+		It("requests to remove the transitional flag when no version is given", func() {
+			dummy := &DummyAuth{Response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString("")),
+			}}
+
+			ch, _ := New("https://example.com", Auth(dummy.Builder()))
+			_, _ = ch.UpdateTransitionalVersion("some-id", "")
+
+			body, _ := io.ReadAll(dummy.Request.Body)
+			Expect(body).To(MatchJSON(`{"version": null}`))
+		})
+
+		It("returns the active versions", func() {
+			responseString := `[
+	  {"id": "some-version-id", "name": "/some-ca", "type": "certificate", "value": {"certificate": "new-certificate"}},
+	  {"id": "some-other-version-id", "name": "/some-ca", "type": "certificate", "value": {"certificate": "old-certificate"}}
+	]`
+			dummy := &DummyAuth{Response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString(responseString)),
+			}}
+
+			ch, _ := New("https://example.com", Auth(dummy.Builder()))
+			versions, err := ch.UpdateTransitionalVersion("some-id", "some-other-version-id")
+			Expect(err).To(BeNil())
+			Expect(versions).To(HaveLen(2))
+			Expect(versions[0].Id).To(Equal("some-version-id"))
+			Expect(versions[1].Value.Certificate).To(Equal("old-certificate"))
+		})
+	})
+
+	Context("managing certificate versions", func() {
+		It("requests the current versions of a certificate", func() {
+			dummy := &DummyAuth{Response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString("")),
+			}}
+
+			ch, _ := New("https://example.com", Auth(dummy.Builder()))
+			_, _ = ch.GetCertificateVersions("some-id", true)
+			Expect(dummy.Request.URL.String()).To(Equal("https://example.com/api/v1/certificates/some-id/versions?current=true"))
+			Expect(dummy.Request.Method).To(Equal(http.MethodGet))
+		})
+
+		It("returns the versions with their certificate attributes", func() {
+			responseString := `[{
+	  "id": "some-version-id",
+	  "name": "/some-ca",
+	  "type": "certificate",
+	  "value": {"certificate": "some-certificate"},
+	  "expiry_date": "2020-05-29T12:33:50Z",
+	  "transitional": true,
+	  "certificate_authority": true,
+	  "self_signed": false
+	}]`
+			dummy := &DummyAuth{Response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString(responseString)),
+			}}
+
+			ch, _ := New("https://example.com", Auth(dummy.Builder()))
+			versions, err := ch.GetCertificateVersions("some-id", false)
+			Expect(err).To(BeNil())
+			Expect(dummy.Request.URL.String()).To(Equal("https://example.com/api/v1/certificates/some-id/versions"))
+			Expect(versions).To(HaveLen(1))
+			Expect(versions[0].Id).To(Equal("some-version-id"))
+			Expect(versions[0].Value.Certificate).To(Equal("some-certificate"))
+			Expect(versions[0].ExpiryDate).To(Equal("2020-05-29T12:33:50Z"))
+			Expect(versions[0].Transitional).To(BeTrue())
+			Expect(versions[0].CertificateAuthority).To(BeTrue())
+		})
+
+		It("requests to create a version", func() {
+			dummy := &DummyAuth{Response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"id": "some-version-id"}`)),
+			}}
+
+			ch, _ := New("https://example.com", Auth(dummy.Builder()))
+			version, err := ch.CreateCertificateVersion("some-id", values.Certificate{Ca: "some-ca", Certificate: "some-certificate", PrivateKey: "some-private-key"}, true)
+			Expect(err).To(BeNil())
+			Expect(version.Id).To(Equal("some-version-id"))
+			Expect(dummy.Request.URL.String()).To(Equal("https://example.com/api/v1/certificates/some-id/versions"))
+			Expect(dummy.Request.Method).To(Equal(http.MethodPost))
+
+			body, _ := io.ReadAll(dummy.Request.Body)
+			Expect(body).To(MatchJSON(`{
+	  "value": {"ca": "some-ca", "certificate": "some-certificate", "private_key": "some-private-key"},
+	  "transitional": true
+	}`))
+		})
+
+		It("requests to delete a version", func() {
+			dummy := &DummyAuth{Response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"id": "some-version-id"}`)),
+			}}
+
+			ch, _ := New("https://example.com", Auth(dummy.Builder()))
+			version, err := ch.DeleteCertificateVersion("some-id", "some-version-id")
+			Expect(err).To(BeNil())
+			Expect(version.Id).To(Equal("some-version-id"))
+			Expect(dummy.Request.URL.String()).To(Equal("https://example.com/api/v1/certificates/some-id/versions/some-version-id"))
+			Expect(dummy.Request.Method).To(Equal(http.MethodDelete))
+		})
+	})
 })
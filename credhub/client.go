@@ -5,6 +5,7 @@ import (
 	"crypto/x509"
 	"net"
 	"net/http"
+	"os"
 	"time"
 
 	proxy "github.com/cloudfoundry/socks5-proxy"
@@ -20,11 +21,23 @@ func (ch *CredHub) Client() *http.Client {
 }
 
 func (ch *CredHub) client() *http.Client {
+	var client *http.Client
+
 	if ch.baseURL.Scheme == "https" {
-		return httpsClient(ch.insecureSkipVerify, ch.caCerts, ch.clientCertificate, ch.httpTimeout)
+		client = httpsClient(ch.insecureSkipVerify, ch.caCerts, ch.clientCertificate, ch.httpTimeout)
+	} else {
+		client = httpClient(ch.httpTimeout)
+	}
+
+	if ch.logger != nil || os.Getenv("CREDHUB_DEBUG") == "true" {
+		client.Transport = newLoggingTransport(ch.log(), client.Transport)
 	}
 
-	return httpClient(ch.httpTimeout)
+	if len(ch.interceptors) > 0 {
+		client.Transport = intercept(client.Transport, ch.interceptors)
+	}
+
+	return client
 }
 
 func httpClient(timeout *time.Duration) *http.Client {
@@ -38,11 +51,13 @@ func httpClient(timeout *time.Duration) *http.Client {
 	}
 }
 
-func httpsClient(insecureSkipVerify bool, rootCAs *x509.CertPool, cert *tls.Certificate, timeout *time.Duration) *http.Client {
+func httpsClient(insecureSkipVerify bool, rootCAs *x509.CertPool, cert *clientCertificate, timeout *time.Duration) *http.Client {
 	client := httpClient(timeout)
 	var certs []tls.Certificate
+	var getClientCertificate func(*tls.CertificateRequestInfo) (*tls.Certificate, error)
 	if cert != nil {
-		certs = []tls.Certificate{*cert}
+		certs = []tls.Certificate{*cert.current()}
+		getClientCertificate = cert.getClientCertificate
 	}
 
 	var dialer = SOCKS5DialFuncFromEnvironment((&net.Dialer{
@@ -55,6 +70,7 @@ func httpsClient(insecureSkipVerify bool, rootCAs *x509.CertPool, cert *tls.Cert
 			InsecureSkipVerify:       insecureSkipVerify,
 			PreferServerCipherSuites: true,
 			Certificates:             certs,
+			GetClientCertificate:     getClientCertificate,
 			RootCAs:                  rootCAs,
 			MinVersion:               tls.VersionTLS12,
 		},
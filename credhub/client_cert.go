@@ -0,0 +1,95 @@
+package credhub
+
+import (
+	"crypto/tls"
+	"sync"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+	"code.cloudfoundry.org/credhub-cli/credhub/internal/fileversion"
+)
+
+// clientCertificate is a certificate and key pair loaded from disk, which is reloaded
+// when either file changes so that rotated certificates are picked up without restarting.
+type clientCertificate struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	certMod fileversion.Version
+	keyMod  fileversion.Version
+}
+
+func loadClientCertificate(certFile, keyFile string) (*clientCertificate, error) {
+	c := &clientCertificate{certFile: certFile, keyFile: keyFile}
+
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// current returns the certificate, reloading it first if the files on disk have changed.
+//
+// A certificate that fails to load, eg. because only one of the files has been replaced
+// so far, is ignored and the previous certificate is kept.
+func (c *clientCertificate) current() *tls.Certificate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	certMod, certErr := fileversion.Stat(c.certFile)
+	keyMod, keyErr := fileversion.Stat(c.keyFile)
+
+	if certErr == nil && keyErr == nil && (certMod != c.certMod || keyMod != c.keyMod) {
+		c.load()
+	}
+
+	return c.cert
+}
+
+// getClientCertificate implements tls.Config.GetClientCertificate
+func (c *clientCertificate) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return c.current(), nil
+}
+
+func (c *clientCertificate) reload() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.load()
+}
+
+func (c *clientCertificate) load() error {
+	certMod, err := fileversion.Stat(c.certFile)
+	if err != nil {
+		return err
+	}
+
+	keyMod, err := fileversion.Stat(c.keyFile)
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return err
+	}
+
+	c.cert = &cert
+	c.certMod = certMod
+	c.keyMod = keyMod
+
+	return nil
+}
+
+// ClientCertificate returns the client certificate presented to the CredHub and auth servers,
+// or nil when the ClientCert() option is not used. See the ClientCert() option.
+func (ch *CredHub) ClientCertificate() *tls.Certificate {
+	if ch.clientCertificate == nil {
+		return nil
+	}
+	return ch.clientCertificate.current()
+}
+
+var _ auth.MutualTLSConfig = new(CredHub)
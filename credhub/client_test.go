@@ -78,6 +78,75 @@ var _ = Describe("Client()", func() {
 			_, err := New("https://example.com", ClientCert("./fixtures/auth-tls-key.pem", "./fixtures/auth-tls-cert.pem"))
 			Expect(err).To(HaveOccurred())
 		})
+
+		Context("when the cert and key files are rotated", func() {
+			var (
+				dir               string
+				certFile, keyFile string
+				original, rotated []byte
+				copyFixture       func(fixture, dest string)
+				touchInTheFuture  func(name string)
+			)
+
+			BeforeEach(func() {
+				dir = GinkgoT().TempDir()
+				certFile = dir + "/cert.pem"
+				keyFile = dir + "/key.pem"
+
+				copyFixture = func(fixture, dest string) {
+					contents, err := os.ReadFile("./fixtures/" + fixture)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(os.WriteFile(dest, contents, 0600)).To(Succeed())
+				}
+				touchInTheFuture = func(name string) {
+					future := time.Now().Add(time.Minute)
+					Expect(os.Chtimes(name, future, future)).To(Succeed())
+				}
+
+				copyFixture("auth-tls-cert.pem", certFile)
+				copyFixture("auth-tls-key.pem", keyFile)
+
+				originalPair, err := tls.LoadX509KeyPair("./fixtures/auth-tls-cert.pem", "./fixtures/auth-tls-key.pem")
+				Expect(err).NotTo(HaveOccurred())
+				original = originalPair.Certificate[0]
+
+				rotatedPair, err := tls.LoadX509KeyPair("./fixtures/server-tls-cert.pem", "./fixtures/server-tls-key.pem")
+				Expect(err).NotTo(HaveOccurred())
+				rotated = rotatedPair.Certificate[0]
+			})
+
+			It("presents the rotated certificate on new connections", func() {
+				ch, err := New("https://example.com", ClientCert(certFile, keyFile))
+				Expect(err).NotTo(HaveOccurred())
+
+				tlsConfig := ch.Client().Transport.(*http.Transport).TLSClientConfig
+				Expect(tlsConfig.GetClientCertificate).NotTo(BeNil())
+
+				cert, err := tlsConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cert.Certificate[0]).To(Equal(original))
+
+				copyFixture("server-tls-cert.pem", certFile)
+				copyFixture("server-tls-key.pem", keyFile)
+				touchInTheFuture(certFile)
+				touchInTheFuture(keyFile)
+
+				cert, err = tlsConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cert.Certificate[0]).To(Equal(rotated))
+				Expect(ch.ClientCertificate().Certificate[0]).To(Equal(rotated))
+			})
+
+			It("keeps the previous certificate while the rotation is incomplete", func() {
+				ch, err := New("https://example.com", ClientCert(certFile, keyFile))
+				Expect(err).NotTo(HaveOccurred())
+
+				copyFixture("server-tls-cert.pem", certFile)
+				touchInTheFuture(certFile)
+
+				Expect(ch.ClientCertificate().Certificate[0]).To(Equal(original))
+			})
+		})
 	})
 
 	Context("With CaCerts", func() {
@@ -0,0 +1,99 @@
+package credentials
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Typed is a credential of a known type
+type Typed interface {
+	Value | JSON | Password | User | Certificate | RSA | SSH
+}
+
+// TypeMismatchError is returned when a credential is converted to a type other than its own
+type TypeMismatchError struct {
+	Name     string
+	Type     string
+	Expected string
+}
+
+func (e *TypeMismatchError) Error() string {
+	return fmt.Sprintf("the credential '%s' has type '%s', not '%s'", e.Name, e.Type, e.Expected)
+}
+
+// TypeOf returns the CredHub type of the typed credential T, eg. "certificate" for Certificate
+func TypeOf[T Typed]() string {
+	var typed T
+	switch any(typed).(type) {
+	case Value:
+		return "value"
+	case JSON:
+		return "json"
+	case Password:
+		return "password"
+	case User:
+		return "user"
+	case Certificate:
+		return "certificate"
+	case RSA:
+		return "rsa"
+	default:
+		return "ssh"
+	}
+}
+
+// As converts a generic credential into the typed credential T, decoding its value. It returns a
+// *TypeMismatchError when the credential has another type.
+func As[T Typed](c Credential) (T, error) {
+	var typed T
+	if expected := TypeOf[T](); c.Type != expected {
+		return typed, &TypeMismatchError{Name: c.Name, Type: c.Type, Expected: expected}
+	}
+
+	// Credential has its own encoding, which drops the fields the typed credentials decode
+	data, err := json.Marshal(struct {
+		Base
+		Value interface{} `json:"value"`
+	}{c.Base, c.Value})
+	if err != nil {
+		return typed, err
+	}
+
+	err = json.Unmarshal(data, &typed)
+	return typed, err
+}
+
+// AsValue converts the credential into a Value credential
+func (c Credential) AsValue() (Value, error) {
+	return As[Value](c)
+}
+
+// AsJSON converts the credential into a JSON credential
+func (c Credential) AsJSON() (JSON, error) {
+	return As[JSON](c)
+}
+
+// AsPassword converts the credential into a Password credential
+func (c Credential) AsPassword() (Password, error) {
+	return As[Password](c)
+}
+
+// AsUser converts the credential into a User credential
+func (c Credential) AsUser() (User, error) {
+	return As[User](c)
+}
+
+// AsCertificate converts the credential into a Certificate credential
+func (c Credential) AsCertificate() (Certificate, error) {
+	return As[Certificate](c)
+}
+
+// AsRSA converts the credential into an RSA credential
+func (c Credential) AsRSA() (RSA, error) {
+	return As[RSA](c)
+}
+
+// AsSSH converts the credential into an SSH credential
+func (c Credential) AsSSH() (SSH, error) {
+	return As[SSH](c)
+}
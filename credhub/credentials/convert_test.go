@@ -0,0 +1,73 @@
+package credentials_test
+
+import (
+	"encoding/json"
+
+	. "code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/values"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Converting credentials", func() {
+	var cred Credential
+
+	BeforeEach(func() {
+		cred = Credential{}
+		Expect(json.Unmarshal([]byte(`{
+	"id": "some-id",
+	"name": "/example-certificate",
+	"type": "certificate",
+	"duration_overridden": true,
+	"duration_used": 1234,
+	"value": {
+		"ca": "some-ca",
+		"ca_name": "/some-ca",
+		"certificate": "some-certificate",
+		"private_key": "some-private-key"
+	},
+	"metadata": {"some": "metadata"},
+	"version_created_at": "2017-01-01T04:07:18Z"
+}`), &cred)).To(Succeed())
+	})
+
+	It("decodes the value into the typed credential", func() {
+		cert, err := cred.AsCertificate()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert.Base).To(Equal(cred.Base))
+		Expect(cert.Value).To(Equal(values.Certificate{
+			Ca:          "some-ca",
+			CaName:      "/some-ca",
+			Certificate: "some-certificate",
+			PrivateKey:  "some-private-key",
+		}))
+	})
+
+	It("returns an error for another type", func() {
+		_, err := cred.AsPassword()
+
+		var mismatch *TypeMismatchError
+		Expect(err).To(BeAssignableToTypeOf(mismatch))
+		Expect(err).To(MatchError("the credential '/example-certificate' has type 'certificate', not 'password'"))
+	})
+
+	It("decodes string values", func() {
+		cred = Credential{Base: Base{Name: "/some-value", Type: "value"}, Value: "some-value"}
+
+		value, err := As[Value](cred)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value.Value).To(Equal(values.Value("some-value")))
+	})
+
+	It("names the type of each typed credential", func() {
+		Expect(TypeOf[Value]()).To(Equal("value"))
+		Expect(TypeOf[JSON]()).To(Equal("json"))
+		Expect(TypeOf[Password]()).To(Equal("password"))
+		Expect(TypeOf[User]()).To(Equal("user"))
+		Expect(TypeOf[Certificate]()).To(Equal("certificate"))
+		Expect(TypeOf[RSA]()).To(Equal("rsa"))
+		Expect(TypeOf[SSH]()).To(Equal("ssh"))
+	})
+})
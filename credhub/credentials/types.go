@@ -101,6 +101,15 @@ type Certificate struct {
 	Value values.Certificate `json:"value"`
 }
 
+// A version of a Certificate type credential, with the attributes of its certificate
+type CertificateVersion struct {
+	Certificate          `yaml:",inline"`
+	ExpiryDate           string `json:"expiry_date" yaml:"expiry_date"`
+	Transitional         bool   `json:"transitional" yaml:"transitional"`
+	CertificateAuthority bool   `json:"certificate_authority" yaml:"certificate_authority"`
+	SelfSigned           bool   `json:"self_signed" yaml:"self_signed"`
+}
+
 // An RSA type credential
 type RSA struct {
 	Base  `yaml:",inline"`
@@ -586,4 +586,57 @@ versions:
 			Expect(yamlOutput).To(MatchYAML(metadataYaml))
 		})
 	})
+
+	Describe("Certificate Version", func() {
+		Specify("when decoding and encoding", func() {
+			versionJSON := `{
+      "id": "some-version-id",
+      "name": "/some-ca",
+      "type": "certificate",
+      "metadata": {"some": "thing"},
+      "version_created_at": "2017-01-01T04:07:18Z",
+      "value": {
+        "ca": "some-ca",
+        "certificate": "some-certificate",
+        "private_key": "some-private-key"
+      },
+      "expiry_date": "2020-05-29T12:33:50Z",
+      "transitional": true,
+      "certificate_authority": true,
+      "self_signed": true
+    }`
+
+			versionYaml := `
+id: some-version-id
+name: "/some-ca"
+type: certificate
+metadata:
+  some: thing
+version_created_at: '2017-01-01T04:07:18Z'
+value:
+  ca: some-ca
+  certificate: some-certificate
+  private_key: some-private-key
+expiry_date: '2020-05-29T12:33:50Z'
+transitional: true
+certificate_authority: true
+self_signed: true
+`
+			var version CertificateVersion
+			Expect(json.Unmarshal([]byte(versionJSON), &version)).To(Succeed())
+
+			Expect(version.Id).To(Equal("some-version-id"))
+			Expect(version.Value.Certificate).To(Equal("some-certificate"))
+			Expect(version.ExpiryDate).To(Equal("2020-05-29T12:33:50Z"))
+			Expect(version.Transitional).To(BeTrue())
+
+			jsonOutput, err := json.Marshal(version)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jsonOutput).To(MatchJSON(versionJSON))
+
+			yamlOutput, err := yaml.Marshal(version)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(yamlOutput).To(MatchYAML(versionYaml))
+		})
+	})
 })
@@ -8,14 +8,16 @@ Server HTTP API specification can be found at https://docs.cloudfoundry.org/api/
 package credhub
 
 import (
+	"context"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"time"
 
-	"crypto/tls"
 	"crypto/x509"
 
 	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+	"code.cloudfoundry.org/credhub-cli/credhub/metrics"
 )
 
 // CredHub client to access CredHub APIs.
@@ -37,8 +39,8 @@ type CredHub struct {
 	// Trusted CA certificates in PEM format for making TLS connections to CredHub and auth servers
 	caCerts *x509.CertPool
 
-	// client certificates
-	clientCertificate *tls.Certificate
+	// client certificate, reloaded when its files change
+	clientCertificate *clientCertificate
 
 	// Skip certificate verification of TLS connections to CredHub and auth servers. Not recommended!
 	insecureSkipVerify bool
@@ -51,4 +53,48 @@ type CredHub struct {
 
 	// Timeout for http client
 	httpTimeout *time.Duration
+
+	// Policy for retrying requests that fail with a transient error. Retries are disabled by default
+	retryPolicy RetryPolicy
+
+	// Destination of diagnostic messages. See Logger()
+	logger *slog.Logger
+
+	// Chain of interceptors wrapping the transport of Client(). See Interceptors()
+	interceptors []Interceptor
+
+	// Destination of request and token refresh measurements. See Metrics()
+	metrics metrics.Recorder
+
+	// Context attached to every request made by this client. See WithContext()
+	ctx context.Context
+}
+
+// WithContext returns a shallow copy of the client whose requests are bound to ctx.
+//
+// Cancelling ctx or exceeding its deadline aborts in-flight requests to the CredHub server,
+// including any token grants the auth Strategy makes to refresh its credentials.
+// The copy shares the auth Strategy and HTTP connections of the original client.
+func (ch *CredHub) WithContext(ctx context.Context) *CredHub {
+	if ctx == nil {
+		panic("nil context")
+	}
+
+	// initialize the shared http.Client before copying so both clients reuse its connections
+	ch.Client()
+
+	clone := *ch
+	clone.ctx = ctx
+
+	return &clone
+}
+
+// Context returns the context attached to the client's requests.
+//
+// The returned context is always non-nil; it defaults to context.Background().
+func (ch *CredHub) Context() context.Context {
+	if ch.ctx != nil {
+		return ch.ctx
+	}
+	return context.Background()
 }
@@ -0,0 +1,638 @@
+package credhubtest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"sort"
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/generate"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/values"
+)
+
+const (
+	errCANotFound          = "The request could not be completed because the CA does not exist or you do not have sufficient authorization."
+	errInvalidCA           = "The provided CA is not a valid certificate authority. Please use a valid CA and retry your request."
+	errInvalidCertificate  = "The provided certificate value is not a valid X509 certificate."
+	errCertificateSigner   = "The combination of parameters in the request is not allowed. Please validate your input and retry your request."
+	errCertificateDuration = "The provided duration must be between 1 and 3650 days."
+	errTransitionalNotCA   = "Transitional versions are only allowed for certificate authorities."
+	errTooManyTransitional = "The maximum number of transitional versions for a given CA is 1."
+	errVersionNotFound     = "The provided certificate version does not exist. Please validate your input and retry your request."
+	errLastVersion         = "The minimum number of versions for a Certificate is 1."
+)
+
+const defaultCertificateDuration = 365
+
+var keyUsages = map[string]x509.KeyUsage{
+	"digital_signature": x509.KeyUsageDigitalSignature,
+	"non_repudiation":   x509.KeyUsageContentCommitment,
+	"key_encipherment":  x509.KeyUsageKeyEncipherment,
+	"data_encipherment": x509.KeyUsageDataEncipherment,
+	"key_agreement":     x509.KeyUsageKeyAgreement,
+	"key_cert_sign":     x509.KeyUsageCertSign,
+	"crl_sign":          x509.KeyUsageCRLSign,
+	"encipher_only":     x509.KeyUsageEncipherOnly,
+	"decipher_only":     x509.KeyUsageDecipherOnly,
+}
+
+var extKeyUsages = map[string]x509.ExtKeyUsage{
+	"server_auth":      x509.ExtKeyUsageServerAuth,
+	"client_auth":      x509.ExtKeyUsageClientAuth,
+	"code_signing":     x509.ExtKeyUsageCodeSigning,
+	"email_protection": x509.ExtKeyUsageEmailProtection,
+	"timestamping":     x509.ExtKeyUsageTimeStamping,
+}
+
+// signingCA returns the value of the named CA which signs new certificates, its latest version which is not
+// transitional
+func (s *Server) signingCA(name string) (values.Certificate, *apiError) {
+	c, ok := s.credentials[name]
+	if !ok {
+		return values.Certificate{}, notFound(errCANotFound)
+	}
+	if c.typ != "certificate" {
+		return values.Certificate{}, badRequest(errInvalidCA)
+	}
+
+	for i := len(c.versions) - 1; i >= 0; i-- {
+		if !c.versions[i].transitional {
+			return c.versions[i].value.(values.Certificate), nil
+		}
+	}
+	return c.latest().value.(values.Certificate), nil
+}
+
+func (s *Server) generateCertificate(p generate.Certificate) (interface{}, *apiError) {
+	if p.Ca == "" && !p.IsCA && !p.SelfSign {
+		return nil, badRequest(errCertificateSigner)
+	}
+	if p.Duration < 1 || p.Duration > 3650 {
+		return nil, badRequest(errCertificateDuration)
+	}
+
+	key, apiErr := generateKey(p.KeyLength)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	template, apiErr := certificateTemplate(p, &key.PublicKey)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	parent := template
+	var signer crypto.Signer = key
+	var caValue values.Certificate
+	if p.Ca != "" {
+		caValue, apiErr = s.signingCA(p.Ca)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		parent, signer, apiErr = parseCA(caValue)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		return nil, &apiError{status: http.StatusInternalServerError, description: err.Error()}
+	}
+	certificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	value := values.Certificate{
+		Ca:          certificate,
+		Certificate: certificate,
+		PrivateKey:  encodePrivateKey(key),
+	}
+	if p.Ca != "" {
+		value.Ca = caValue.Certificate
+		value.CaName = p.Ca
+	}
+	return value, nil
+}
+
+func certificateTemplate(p generate.Certificate, publicKey *rsa.PublicKey) (*x509.Certificate, *apiError) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, &apiError{status: http.StatusInternalServerError, description: err.Error()}
+	}
+
+	subjectKeyID := sha1.Sum(x509.MarshalPKCS1PublicKey(publicKey))
+	now := time.Now()
+
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			CommonName:         p.CommonName,
+			Organization:       nonEmpty(p.Organization),
+			OrganizationalUnit: nonEmpty(p.OrganizationUnit),
+			Locality:           nonEmpty(p.Locality),
+			Province:           nonEmpty(p.State),
+			Country:            nonEmpty(p.Country),
+		},
+		NotBefore:             now,
+		NotAfter:              now.AddDate(0, 0, p.Duration),
+		SubjectKeyId:          subjectKeyID[:],
+		BasicConstraintsValid: true,
+		IsCA:                  p.IsCA,
+	}
+
+	for _, name := range p.AlternativeNames {
+		if ip := net.ParseIP(name); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, name)
+		}
+	}
+
+	for _, usage := range p.KeyUsage {
+		keyUsage, ok := keyUsages[usage]
+		if !ok {
+			return nil, badRequest("The provided key usage '" + usage + "' is not supported.")
+		}
+		template.KeyUsage |= keyUsage
+	}
+	for _, usage := range p.ExtendedKeyUsage {
+		extKeyUsage, ok := extKeyUsages[usage]
+		if !ok {
+			return nil, badRequest("The provided extended key usage '" + usage + "' is not supported.")
+		}
+		template.ExtKeyUsage = append(template.ExtKeyUsage, extKeyUsage)
+	}
+	if p.IsCA {
+		template.KeyUsage |= x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	}
+
+	return template, nil
+}
+
+// certificateParameters returns the parameters which regenerate a certificate which was set rather than generated
+func certificateParameters(value values.Certificate) (json.RawMessage, *apiError) {
+	cert, err := parseCertificate(value.Certificate)
+	if err != nil {
+		return nil, badRequest(errInvalidCertificate)
+	}
+
+	p := generate.Certificate{
+		KeyLength:  defaultKeyLength,
+		Duration:   int(cert.NotAfter.Sub(cert.NotBefore).Round(24*time.Hour) / (24 * time.Hour)),
+		CommonName: cert.Subject.CommonName,
+		Ca:         value.CaName,
+		IsCA:       cert.IsCA,
+		SelfSign:   value.CaName == "" && !cert.IsCA,
+	}
+	if key, ok := cert.PublicKey.(*rsa.PublicKey); ok {
+		p.KeyLength = key.N.BitLen()
+	}
+	p.Organization = first(cert.Subject.Organization)
+	p.OrganizationUnit = first(cert.Subject.OrganizationalUnit)
+	p.Locality = first(cert.Subject.Locality)
+	p.State = first(cert.Subject.Province)
+	p.Country = first(cert.Subject.Country)
+
+	p.AlternativeNames = append(p.AlternativeNames, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		p.AlternativeNames = append(p.AlternativeNames, ip.String())
+	}
+	for name, usage := range keyUsages {
+		if cert.KeyUsage&usage != 0 && !(cert.IsCA && (usage == x509.KeyUsageCertSign || usage == x509.KeyUsageCRLSign)) {
+			p.KeyUsage = append(p.KeyUsage, name)
+		}
+	}
+	sort.Strings(p.KeyUsage)
+	for name, usage := range extKeyUsages {
+		for _, certUsage := range cert.ExtKeyUsage {
+			if certUsage == usage {
+				p.ExtendedKeyUsage = append(p.ExtendedKeyUsage, name)
+			}
+		}
+	}
+	sort.Strings(p.ExtendedKeyUsage)
+
+	parameters, _ := json.Marshal(p)
+	return parameters, nil
+}
+
+func parseCA(value values.Certificate) (*x509.Certificate, crypto.Signer, *apiError) {
+	cert, err := parseCertificate(value.Certificate)
+	if err != nil || !cert.IsCA {
+		return nil, nil, badRequest(errInvalidCA)
+	}
+
+	key, err := parsePrivateKey(value.PrivateKey)
+	if err != nil {
+		return nil, nil, badRequest(errInvalidCA)
+	}
+
+	return cert, key, nil
+}
+
+func parseCertificate(certificate string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(certificate))
+	if block == nil {
+		return nil, errNoPEM
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func parsePrivateKey(privateKey string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(privateKey))
+	if block == nil {
+		return nil, errNoPEM
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errNoPEM
+	}
+	return signer, nil
+}
+
+var errNoPEM = errors.New("no PEM data found")
+
+func nonEmpty(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (s *Server) getCertificates(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	certificates := []credentials.CertificateMetadata{}
+	if query.Has("name") {
+		c, ok := s.credentials[normalizeName(query.Get("name"))]
+		if !ok || c.typ != "certificate" {
+			writeError(w, http.StatusNotFound, errCredentialNotFound)
+			return
+		}
+		certificates = append(certificates, s.certificateMetadata(c))
+	} else {
+		for _, c := range s.sortedCredentials() {
+			if c.typ == "certificate" {
+				certificates = append(certificates, s.certificateMetadata(c))
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"certificates": certificates})
+}
+
+func (s *Server) sortedCredentials() []*credential {
+	sorted := []*credential{}
+	for _, c := range s.credentials {
+		sorted = append(sorted, c)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].name < sorted[j].name
+	})
+	return sorted
+}
+
+// caName returns the name of the CA which signed the latest version of the certificate, which is the
+// certificate itself when it is self-signed
+func (c *credential) caName() string {
+	value := c.latest().value.(values.Certificate)
+	if value.CaName != "" {
+		return value.CaName
+	}
+	if cert, err := parseCertificate(value.Certificate); err == nil && isSelfSigned(cert) {
+		return c.name
+	}
+	return ""
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return cert.CheckSignatureFrom(cert) == nil
+}
+
+func (s *Server) certificateMetadata(c *credential) credentials.CertificateMetadata {
+	metadata := credentials.CertificateMetadata{
+		Id:       c.id,
+		Name:     c.name,
+		SignedBy: c.caName(),
+		Signs:    []string{},
+		Versions: []credentials.CertificateMetadataVersion{},
+	}
+
+	for _, other := range s.sortedCredentials() {
+		if other != c && other.typ == "certificate" && other.caName() == c.name {
+			metadata.Signs = append(metadata.Signs, other.name)
+		}
+	}
+
+	for _, v := range c.activeVersions() {
+		metadata.Versions = append(metadata.Versions, metadataVersion(v))
+	}
+
+	return metadata
+}
+
+func metadataVersion(v *version) credentials.CertificateMetadataVersion {
+	version := credentials.CertificateMetadataVersion{Id: v.id, Transitional: v.transitional}
+	if cert, err := parseCertificate(v.value.(values.Certificate).Certificate); err == nil {
+		version.ExpiryDate = timestamp(cert.NotAfter)
+		version.CertificateAuthority = cert.IsCA
+		version.SelfSigned = isSelfSigned(cert)
+	}
+	return version
+}
+
+// certificateVersionResponse is a version of a certificate as the certificates endpoints return it, with the
+// attributes of its certificate
+type certificateVersionResponse struct {
+	credentialResponse
+	ExpiryDate           string `json:"expiry_date"`
+	Transitional         bool   `json:"transitional"`
+	CertificateAuthority bool   `json:"certificate_authority"`
+	SelfSigned           bool   `json:"self_signed"`
+}
+
+func (c *credential) certificateResponse(v *version) certificateVersionResponse {
+	attributes := metadataVersion(v)
+	return certificateVersionResponse{
+		credentialResponse:   c.response(v),
+		ExpiryDate:           attributes.ExpiryDate,
+		Transitional:         attributes.Transitional,
+		CertificateAuthority: attributes.CertificateAuthority,
+		SelfSigned:           attributes.SelfSigned,
+	}
+}
+
+// activeVersions returns the versions of the certificate which are in use, newest first: its latest version
+// which is not transitional and its transitional version
+func (c *credential) activeVersions() []*version {
+	active := []*version{}
+	signing := false
+	for _, v := range c.newestFirst() {
+		if v.transitional || !signing {
+			active = append(active, v)
+			signing = signing || !v.transitional
+		}
+	}
+	return active
+}
+
+// certificateByID returns the certificate with the certificate ID, which is not the ID of any of its versions
+func (s *Server) certificateByID(id string) (*credential, bool) {
+	for _, c := range s.credentials {
+		if c.id == id && c.typ == "certificate" {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+func (s *Server) regenerateCertificate(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		SetAsTransitional bool                 `json:"set_as_transitional"`
+		Metadata          credentials.Metadata `json:"metadata"`
+	}
+	if !decodeBody(w, r, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.certificateByID(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errCredentialNotFound)
+		return
+	}
+
+	if request.SetAsTransitional {
+		cert, err := parseCertificate(c.latest().value.(values.Certificate).Certificate)
+		if err != nil || !cert.IsCA {
+			writeError(w, http.StatusBadRequest, errTransitionalNotCA)
+			return
+		}
+		for _, v := range c.versions {
+			if v.transitional {
+				writeError(w, http.StatusBadRequest, errTooManyTransitional)
+				return
+			}
+		}
+	}
+
+	if _, apiErr := s.regenerate(c.name, request.Metadata); apiErr != nil {
+		apiErr.write(w)
+		return
+	}
+	c.latest().transitional = request.SetAsTransitional
+
+	writeJSON(w, http.StatusOK, c.certificateResponse(c.latest()))
+}
+
+func (s *Server) updateTransitionalVersion(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Version *string `json:"version"`
+	}
+	if !decodeBody(w, r, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.certificateByID(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errCredentialNotFound)
+		return
+	}
+
+	var transitional *version
+	if request.Version != nil {
+		for _, v := range c.versions {
+			if v.id == *request.Version {
+				transitional = v
+			}
+		}
+		if transitional == nil {
+			writeError(w, http.StatusBadRequest, errVersionNotFound)
+			return
+		}
+	}
+
+	for _, v := range c.versions {
+		v.transitional = v == transitional
+	}
+
+	versions := []certificateVersionResponse{}
+	for _, v := range c.activeVersions() {
+		versions = append(versions, c.certificateResponse(v))
+	}
+	writeJSON(w, http.StatusOK, versions)
+}
+
+func (s *Server) getCertificateVersions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.certificateByID(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errCredentialNotFound)
+		return
+	}
+
+	found := c.newestFirst()
+	if r.URL.Query().Get("current") == "true" {
+		found = c.activeVersions()
+	}
+
+	versions := []certificateVersionResponse{}
+	for _, v := range found {
+		versions = append(versions, c.certificateResponse(v))
+	}
+	writeJSON(w, http.StatusOK, versions)
+}
+
+func (s *Server) createCertificateVersion(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Value        json.RawMessage `json:"value"`
+		Transitional bool            `json:"transitional"`
+	}
+	if !decodeBody(w, r, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.certificateByID(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errCredentialNotFound)
+		return
+	}
+
+	value, apiErr := s.parseValue("certificate", request.Value)
+	if apiErr != nil {
+		apiErr.write(w)
+		return
+	}
+	cert, err := parseCertificate(value.(values.Certificate).Certificate)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidCertificate)
+		return
+	}
+
+	if request.Transitional {
+		if !cert.IsCA {
+			writeError(w, http.StatusBadRequest, errTransitionalNotCA)
+			return
+		}
+		for _, v := range c.versions {
+			if v.transitional {
+				writeError(w, http.StatusBadRequest, errTooManyTransitional)
+				return
+			}
+		}
+	}
+
+	if _, apiErr := s.addVersion(c.name, c.typ, &version{value: value, transitional: request.Transitional}); apiErr != nil {
+		apiErr.write(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c.certificateResponse(c.latest()))
+}
+
+func (s *Server) deleteCertificateVersion(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.certificateByID(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errCredentialNotFound)
+		return
+	}
+
+	for i, v := range c.versions {
+		if v.id != r.PathValue("versionID") {
+			continue
+		}
+		if len(c.versions) == 1 {
+			writeError(w, http.StatusBadRequest, errLastVersion)
+			return
+		}
+
+		c.versions = append(c.versions[:i], c.versions[i+1:]...)
+		writeJSON(w, http.StatusOK, c.certificateResponse(v))
+		return
+	}
+
+	writeError(w, http.StatusNotFound, errCredentialNotFound)
+}
+
+func (s *Server) bulkRegenerate(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		SignedBy string `json:"signed_by"`
+	}
+	if !decodeBody(w, r, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	signedBy := normalizeName(request.SignedBy)
+	if c, ok := s.credentials[signedBy]; !ok || c.typ != "certificate" {
+		writeError(w, http.StatusNotFound, errCredentialNotFound)
+		return
+	}
+
+	regenerated := []string{}
+	cas := []string{signedBy}
+	for len(cas) > 0 {
+		ca := cas[0]
+		cas = cas[1:]
+
+		for _, c := range s.sortedCredentials() {
+			if c.name == ca || c.typ != "certificate" || c.caName() != ca {
+				continue
+			}
+
+			if _, apiErr := s.regenerate(c.name, nil); apiErr != nil {
+				apiErr.write(w)
+				return
+			}
+			regenerated = append(regenerated, c.name)
+
+			if cert, err := parseCertificate(c.latest().value.(values.Certificate).Certificate); err == nil && cert.IsCA {
+				cas = append(cas, c.name)
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, credentials.BulkRegenerateResults{Certificates: regenerated})
+}
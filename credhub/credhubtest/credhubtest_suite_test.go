@@ -0,0 +1,13 @@
+package credhubtest_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestCredhubtest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CredHub Test Server Suite")
+}
@@ -0,0 +1,491 @@
+package credhubtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/values"
+)
+
+// credential is a named credential with its versions, oldest first
+type credential struct {
+	id       string
+	name     string
+	typ      string
+	versions []*version
+}
+
+type version struct {
+	id        string
+	value     interface{}
+	metadata  credentials.Metadata
+	createdAt time.Time
+
+	// parameters are those the value was generated with. They are nil for values which were set.
+	parameters json.RawMessage
+
+	// transitional marks a certificate version which is not yet used to sign other certificates
+	transitional bool
+}
+
+type userValue struct {
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	PasswordHash string `json:"password_hash"`
+}
+
+type sshValue struct {
+	values.SSH
+	PublicKeyFingerprint string `json:"public_key_fingerprint"`
+}
+
+type credentialResponse struct {
+	Id               string               `json:"id"`
+	Name             string               `json:"name"`
+	Type             string               `json:"type"`
+	Value            interface{}          `json:"value"`
+	Metadata         credentials.Metadata `json:"metadata"`
+	VersionCreatedAt string               `json:"version_created_at"`
+}
+
+// apiError is an error response of the server
+type apiError struct {
+	status      int
+	description string
+}
+
+func (e *apiError) write(w http.ResponseWriter) {
+	writeError(w, e.status, e.description)
+}
+
+func badRequest(description string) *apiError {
+	return &apiError{status: http.StatusBadRequest, description: description}
+}
+
+func notFound(description string) *apiError {
+	return &apiError{status: http.StatusNotFound, description: description}
+}
+
+const (
+	errNameRequired     = "A credential name must be provided. Please validate your input and retry your request."
+	errQueryRequired    = "The query parameter name is required for this request."
+	errValueRequired    = "A non-empty value must be specified for the credential. Please validate and retry your request."
+	errTypeModified     = "The credential type cannot be modified. Please delete the credential if you wish to create it with a different type."
+	errInvalidSetType   = "The request does not include a valid type. Valid values include 'value', 'json', 'password', 'user', 'certificate', 'ssh' and 'rsa'."
+	errInvalidVersions  = "The number of versions must be a positive integer."
+	errOverwriteAndMode = "The parameters overwrite and mode cannot be combined. Please update and retry your request."
+	errInvalidMode      = "The mode must be one of 'overwrite', 'no-overwrite' or 'converge'."
+)
+
+// normalizeName prefixes the name with a slash, as CredHub does
+func normalizeName(name string) string {
+	if strings.HasPrefix(name, "/") {
+		return name
+	}
+	return "/" + name
+}
+
+func (c *credential) latest() *version {
+	return c.versions[len(c.versions)-1]
+}
+
+func (c *credential) newestFirst() []*version {
+	versions := make([]*version, len(c.versions))
+	for i, v := range c.versions {
+		versions[len(c.versions)-1-i] = v
+	}
+	return versions
+}
+
+func (c *credential) response(v *version) credentialResponse {
+	return credentialResponse{
+		Id:               v.id,
+		Name:             c.name,
+		Type:             c.typ,
+		Value:            v.value,
+		Metadata:         v.metadata,
+		VersionCreatedAt: timestamp(v.createdAt),
+	}
+}
+
+// addVersion saves a new version of the named credential, creating it when it does not exist
+func (s *Server) addVersion(name, typ string, v *version) (*credential, *apiError) {
+	c, ok := s.credentials[name]
+	if !ok {
+		c = &credential{id: newUUID(), name: name, typ: typ}
+		s.credentials[name] = c
+	} else if c.typ != typ {
+		return nil, badRequest(errTypeModified)
+	}
+
+	v.id = newUUID()
+	v.createdAt = time.Now()
+	c.versions = append(c.versions, v)
+
+	return c, nil
+}
+
+func (s *Server) getData(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case query.Has("name"):
+		c, ok := s.credentials[normalizeName(query.Get("name"))]
+		if !ok {
+			writeError(w, http.StatusNotFound, errCredentialNotFound)
+			return
+		}
+
+		versions := c.newestFirst()
+		if query.Get("current") == "true" {
+			versions = versions[:1]
+		} else if query.Has("versions") {
+			n, err := strconv.Atoi(query.Get("versions"))
+			if err != nil || n < 1 {
+				writeError(w, http.StatusBadRequest, errInvalidVersions)
+				return
+			}
+			if n < len(versions) {
+				versions = versions[:n]
+			}
+		}
+
+		data := []credentialResponse{}
+		for _, v := range versions {
+			data = append(data, c.response(v))
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+	case query.Has("path"):
+		path := strings.TrimSuffix(normalizeName(query.Get("path")), "/") + "/"
+		writeJSON(w, http.StatusOK, s.find(func(name string) bool {
+			return strings.HasPrefix(name, path)
+		}))
+	case query.Has("name-like"):
+		nameLike := strings.ToLower(query.Get("name-like"))
+		writeJSON(w, http.StatusOK, s.find(func(name string) bool {
+			return strings.Contains(strings.ToLower(name), nameLike)
+		}))
+	default:
+		writeError(w, http.StatusBadRequest, errQueryRequired)
+	}
+}
+
+// find returns the matching credentials, most recently updated first
+func (s *Server) find(match func(name string) bool) credentials.FindResults {
+	matches := []*credential{}
+	for name, c := range s.credentials {
+		if match(name) {
+			matches = append(matches, c)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].latest().createdAt.Equal(matches[j].latest().createdAt) {
+			return matches[i].latest().createdAt.After(matches[j].latest().createdAt)
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	var results credentials.FindResults
+	results.Credentials = make([]struct {
+		Name             string `json:"name" yaml:"name"`
+		VersionCreatedAt string `json:"version_created_at" yaml:"version_created_at"`
+	}, len(matches))
+	for i, c := range matches {
+		results.Credentials[i].Name = c.name
+		results.Credentials[i].VersionCreatedAt = timestamp(c.latest().createdAt)
+	}
+	return results
+}
+
+func (s *Server) getDataByID(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range s.credentials {
+		for _, v := range c.versions {
+			if v.id == id {
+				writeJSON(w, http.StatusOK, c.response(v))
+				return
+			}
+		}
+	}
+
+	writeError(w, http.StatusNotFound, errCredentialNotFound)
+}
+
+func (s *Server) setData(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Name     string               `json:"name"`
+		Type     string               `json:"type"`
+		Value    json.RawMessage      `json:"value"`
+		Metadata credentials.Metadata `json:"metadata"`
+	}
+	if !decodeBody(w, r, &request) {
+		return
+	}
+
+	if request.Name == "" {
+		writeError(w, http.StatusBadRequest, errNameRequired)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, apiErr := s.parseValue(request.Type, request.Value)
+	if apiErr != nil {
+		apiErr.write(w)
+		return
+	}
+
+	c, apiErr := s.addVersion(normalizeName(request.Name), request.Type, &version{value: value, metadata: request.Metadata})
+	if apiErr != nil {
+		apiErr.write(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c.response(c.latest()))
+}
+
+// parseValue validates a value which is set, completing the fields derived from it
+func (s *Server) parseValue(typ string, raw json.RawMessage) (interface{}, *apiError) {
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, badRequest(errValueRequired)
+	}
+
+	switch typ {
+	case "value", "password":
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil || value == "" {
+			return nil, badRequest(errValueRequired)
+		}
+		return value, nil
+	case "json":
+		var value map[string]interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, badRequest(errInvalidJSON)
+		}
+		return value, nil
+	case "user":
+		var value values.User
+		if err := json.Unmarshal(raw, &value); err != nil || value.Password == "" {
+			return nil, badRequest(errValueRequired)
+		}
+		return userValue{Username: value.Username, Password: value.Password, PasswordHash: passwordHash(value.Password)}, nil
+	case "certificate":
+		var value values.Certificate
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, badRequest(errInvalidJSON)
+		}
+		if value.Ca == "" && value.Certificate == "" && value.PrivateKey == "" {
+			return nil, badRequest(errValueRequired)
+		}
+		if value.CaName != "" {
+			value.CaName = normalizeName(value.CaName)
+			ca, apiErr := s.signingCA(value.CaName)
+			if apiErr != nil {
+				return nil, apiErr
+			}
+			value.Ca = ca.Certificate
+		}
+		return value, nil
+	case "rsa":
+		var value values.RSA
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, badRequest(errInvalidJSON)
+		}
+		if value.PublicKey == "" && value.PrivateKey == "" {
+			return nil, badRequest(errValueRequired)
+		}
+		return value, nil
+	case "ssh":
+		var value sshValue
+		if err := json.Unmarshal(raw, &value.SSH); err != nil {
+			return nil, badRequest(errInvalidJSON)
+		}
+		if value.PublicKey == "" && value.PrivateKey == "" {
+			return nil, badRequest(errValueRequired)
+		}
+		value.PublicKeyFingerprint = sshFingerprint(value.PublicKey)
+		return value, nil
+	default:
+		return nil, badRequest(errInvalidSetType)
+	}
+}
+
+func (s *Server) generateData(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Name       string               `json:"name"`
+		Type       string               `json:"type"`
+		Value      json.RawMessage      `json:"value"`
+		Parameters json.RawMessage      `json:"parameters"`
+		Mode       string               `json:"mode"`
+		Overwrite  bool                 `json:"overwrite"`
+		Regenerate bool                 `json:"regenerate"`
+		Metadata   credentials.Metadata `json:"metadata"`
+	}
+	if !decodeBody(w, r, &request) {
+		return
+	}
+
+	if request.Name == "" {
+		writeError(w, http.StatusBadRequest, errNameRequired)
+		return
+	}
+	name := normalizeName(request.Name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if request.Regenerate {
+		c, apiErr := s.regenerate(name, request.Metadata)
+		if apiErr != nil {
+			apiErr.write(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, c.response(c.latest()))
+		return
+	}
+
+	mode := request.Mode
+	if request.Overwrite && mode != "" {
+		writeError(w, http.StatusBadRequest, errOverwriteAndMode)
+		return
+	}
+	switch {
+	case request.Overwrite:
+		mode = "overwrite"
+	case mode == "":
+		mode = "no-overwrite"
+	case mode != "overwrite" && mode != "no-overwrite" && mode != "converge":
+		writeError(w, http.StatusBadRequest, errInvalidMode)
+		return
+	}
+
+	var username string
+	if request.Type == "user" && len(request.Value) > 0 {
+		var value values.User
+		json.Unmarshal(request.Value, &value)
+		username = value.Username
+	}
+
+	parameters, apiErr := normalizeParameters(request.Type, request.Parameters)
+	if apiErr != nil {
+		apiErr.write(w)
+		return
+	}
+
+	if c, ok := s.credentials[name]; ok {
+		if c.typ != request.Type {
+			writeError(w, http.StatusBadRequest, errTypeModified)
+			return
+		}
+
+		unchanged := mode == "no-overwrite"
+		if mode == "converge" {
+			unchanged = c.latest().parameters != nil && bytes.Equal(c.latest().parameters, parameters)
+			if user, ok := c.latest().value.(userValue); ok && username != "" && username != user.Username {
+				unchanged = false
+			}
+		}
+		if unchanged {
+			writeJSON(w, http.StatusOK, c.response(c.latest()))
+			return
+		}
+	}
+
+	value, apiErr := s.generateValue(request.Type, parameters, username)
+	if apiErr != nil {
+		apiErr.write(w)
+		return
+	}
+
+	c, apiErr := s.addVersion(name, request.Type, &version{value: value, metadata: request.Metadata, parameters: parameters})
+	if apiErr != nil {
+		apiErr.write(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c.response(c.latest()))
+}
+
+const (
+	errRegenerateType     = "Credentials of this type cannot be regenerated. Please adjust the credential type and retry your request."
+	errRegeneratePassword = "The password could not be regenerated because the value was statically set. Only generated passwords may be regenerated."
+	errRegenerateUser     = "The user could not be regenerated because the value was statically set. Only generated users may be regenerated."
+)
+
+// regenerate saves a new version of the named credential, generated with the parameters of its latest version
+func (s *Server) regenerate(name string, metadata credentials.Metadata) (*credential, *apiError) {
+	c, ok := s.credentials[name]
+	if !ok {
+		return nil, notFound(errCredentialNotFound)
+	}
+
+	latest := c.latest()
+	parameters := latest.parameters
+	var username string
+
+	switch c.typ {
+	case "value", "json":
+		return nil, badRequest(errRegenerateType)
+	case "password":
+		if parameters == nil {
+			return nil, badRequest(errRegeneratePassword)
+		}
+	case "user":
+		if parameters == nil {
+			return nil, badRequest(errRegenerateUser)
+		}
+		username = latest.value.(userValue).Username
+	case "certificate":
+		if parameters == nil {
+			var apiErr *apiError
+			parameters, apiErr = certificateParameters(latest.value.(values.Certificate))
+			if apiErr != nil {
+				return nil, apiErr
+			}
+		}
+	case "rsa", "ssh":
+		if parameters == nil {
+			parameters, _ = normalizeParameters(c.typ, nil)
+		}
+	}
+
+	value, apiErr := s.generateValue(c.typ, parameters, username)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return s.addVersion(name, c.typ, &version{value: value, metadata: metadata, parameters: parameters})
+}
+
+func (s *Server) deleteData(w http.ResponseWriter, r *http.Request) {
+	if !r.URL.Query().Has("name") {
+		writeError(w, http.StatusBadRequest, errQueryRequired)
+		return
+	}
+	name := normalizeName(r.URL.Query().Get("name"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.credentials[name]; !ok {
+		writeError(w, http.StatusNotFound, errCredentialNotFound)
+		return
+	}
+
+	delete(s.credentials, name)
+	w.WriteHeader(http.StatusNoContent)
+}
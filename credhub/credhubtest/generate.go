@@ -0,0 +1,337 @@
+package credhubtest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/generate"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/values"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	errGenerateType        = "Credentials of this type cannot be generated. Please adjust the credential type and retry your request."
+	errInvalidGenerateType = "The request does not include a valid type. Valid values for generate include 'password', 'user', 'certificate', 'ssh' and 'rsa'."
+	errPasswordLength      = "The password length must be between 4 and 200 characters. Please adjust the length and retry your request."
+	errPasswordCharacters  = "The combination of parameters in the request is not allowed. Please validate your input and retry your request."
+	errKeyLength           = "The provided key length is not supported. Valid values include '2048', '3072' and '4096'."
+)
+
+const (
+	defaultPasswordLength = 30
+	defaultKeyLength      = 2048
+	usernameLength        = 20
+
+	upperCharacters   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	lowerCharacters   = "abcdefghijklmnopqrstuvwxyz"
+	numberCharacters  = "0123456789"
+	specialCharacters = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+)
+
+// normalizeParameters validates generation parameters and fills in their defaults, so that parameters which
+// generate the same kind of value compare equal
+func normalizeParameters(typ string, raw json.RawMessage) (json.RawMessage, *apiError) {
+	if len(raw) == 0 {
+		raw = []byte("{}")
+	}
+
+	var parameters interface{}
+	switch typ {
+	case "password":
+		p := generate.Password{}
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, badRequest(errInvalidJSON)
+		}
+		if p.Length == 0 {
+			p.Length = defaultPasswordLength
+		}
+		parameters = p
+	case "user":
+		p := generate.User{}
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, badRequest(errInvalidJSON)
+		}
+		if p.Length == 0 {
+			p.Length = defaultPasswordLength
+		}
+		parameters = p
+	case "certificate":
+		p := generate.Certificate{}
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, badRequest(errInvalidJSON)
+		}
+		if p.KeyLength == 0 {
+			p.KeyLength = defaultKeyLength
+		}
+		if p.Duration == 0 {
+			p.Duration = defaultCertificateDuration
+		}
+		if p.Ca != "" {
+			p.Ca = normalizeName(p.Ca)
+		}
+		parameters = p
+	case "rsa":
+		p := generate.RSA{}
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, badRequest(errInvalidJSON)
+		}
+		if p.KeyLength == 0 {
+			p.KeyLength = defaultKeyLength
+		}
+		parameters = p
+	case "ssh":
+		p := generate.SSH{}
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, badRequest(errInvalidJSON)
+		}
+		if p.KeyLength == 0 {
+			p.KeyLength = defaultKeyLength
+		}
+		parameters = p
+	case "value", "json":
+		return nil, badRequest(errGenerateType)
+	default:
+		return nil, badRequest(errInvalidGenerateType)
+	}
+
+	normalized, err := json.Marshal(parameters)
+	if err != nil {
+		return nil, badRequest(errInvalidJSON)
+	}
+	return normalized, nil
+}
+
+// generateValue generates a value of the type with normalized parameters
+func (s *Server) generateValue(typ string, parameters json.RawMessage, username string) (interface{}, *apiError) {
+	switch typ {
+	case "password":
+		var p generate.Password
+		json.Unmarshal(parameters, &p)
+		return generatePassword(p)
+	case "user":
+		var p generate.User
+		json.Unmarshal(parameters, &p)
+		password, apiErr := generatePassword(generate.Password{
+			Length:         p.Length,
+			IncludeSpecial: p.IncludeSpecial,
+			ExcludeNumber:  p.ExcludeNumber,
+			ExcludeUpper:   p.ExcludeUpper,
+			ExcludeLower:   p.ExcludeLower,
+		})
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		if username == "" {
+			username = randomString(upperCharacters+lowerCharacters, usernameLength)
+		}
+		return userValue{Username: username, Password: password, PasswordHash: passwordHash(password)}, nil
+	case "certificate":
+		var p generate.Certificate
+		json.Unmarshal(parameters, &p)
+		return s.generateCertificate(p)
+	case "rsa":
+		var p generate.RSA
+		json.Unmarshal(parameters, &p)
+		key, apiErr := generateKey(p.KeyLength)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		publicKey, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		return values.RSA{
+			PublicKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey})),
+			PrivateKey: encodePrivateKey(key),
+		}, nil
+	case "ssh":
+		var p generate.SSH
+		json.Unmarshal(parameters, &p)
+		key, apiErr := generateKey(p.KeyLength)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		publicKey, _ := ssh.NewPublicKey(&key.PublicKey)
+		authorizedKey := strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(publicKey)), "\n")
+		if p.Comment != "" {
+			authorizedKey += " " + p.Comment
+		}
+		return sshValue{
+			SSH:                  values.SSH{PublicKey: authorizedKey, PrivateKey: encodePrivateKey(key)},
+			PublicKeyFingerprint: sshFingerprint(authorizedKey),
+		}, nil
+	default:
+		return nil, badRequest(errInvalidGenerateType)
+	}
+}
+
+// generatePassword generates a password with at least one character of each included kind
+func generatePassword(p generate.Password) (string, *apiError) {
+	if p.Length < 4 || p.Length > 200 {
+		return "", badRequest(errPasswordLength)
+	}
+
+	var charsets []string
+	if !p.ExcludeUpper {
+		charsets = append(charsets, upperCharacters)
+	}
+	if !p.ExcludeLower {
+		charsets = append(charsets, lowerCharacters)
+	}
+	if !p.ExcludeNumber {
+		charsets = append(charsets, numberCharacters)
+	}
+	if p.IncludeSpecial {
+		charsets = append(charsets, specialCharacters)
+	}
+	if len(charsets) == 0 {
+		return "", badRequest(errPasswordCharacters)
+	}
+
+	password := []byte(randomString(strings.Join(charsets, ""), p.Length))
+	for i, charset := range charsets {
+		password[i] = charset[randomInt(len(charset))]
+	}
+	for i := len(password) - 1; i > 0; i-- {
+		j := randomInt(i + 1)
+		password[i], password[j] = password[j], password[i]
+	}
+
+	return string(password), nil
+}
+
+func generateKey(length int) (*rsa.PrivateKey, *apiError) {
+	if length != 2048 && length != 3072 && length != 4096 {
+		return nil, badRequest(errKeyLength)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, length)
+	if err != nil {
+		return nil, &apiError{status: http.StatusInternalServerError, description: err.Error()}
+	}
+	return key, nil
+}
+
+func encodePrivateKey(key *rsa.PrivateKey) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
+
+// sshFingerprint returns the unpadded base64 SHA-256 fingerprint of an authorized key, as CredHub does
+func sshFingerprint(authorizedKey string) string {
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(authorizedKey))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(ssh.FingerprintSHA256(publicKey), "SHA256:")
+}
+
+func randomInt(n int) int {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		panic(err)
+	}
+	return int(i.Int64())
+}
+
+func randomString(charset string, length int) string {
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = charset[randomInt(len(charset))]
+	}
+	return string(b)
+}
+
+const cryptCharacters = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// passwordHash hashes the password with a random salt, in the SHA-512 crypt format CredHub uses for users
+func passwordHash(password string) string {
+	return sha512Crypt(password, randomString(cryptCharacters, 16))
+}
+
+// sha512Crypt implements the SHA-512 crypt scheme ("$6$") with the default 5000 rounds
+func sha512Crypt(password, salt string) string {
+	p := []byte(password)
+	s := []byte(salt)
+
+	b := sha512.New()
+	b.Write(p)
+	b.Write(s)
+	b.Write(p)
+	digestB := b.Sum(nil)
+
+	a := sha512.New()
+	a.Write(p)
+	a.Write(s)
+	for i := len(p); i > 0; i -= 64 {
+		a.Write(digestB[:min(i, 64)])
+	}
+	for i := len(p); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			a.Write(digestB)
+		} else {
+			a.Write(p)
+		}
+	}
+	digestA := a.Sum(nil)
+
+	dp := sha512.New()
+	for range p {
+		dp.Write(p)
+	}
+	sequenceP := repeatDigest(dp.Sum(nil), len(p))
+
+	ds := sha512.New()
+	for i := 0; i < 16+int(digestA[0]); i++ {
+		ds.Write(s)
+	}
+	sequenceS := repeatDigest(ds.Sum(nil), len(s))
+
+	digest := digestA
+	for i := 0; i < 5000; i++ {
+		c := sha512.New()
+		if i&1 != 0 {
+			c.Write(sequenceP)
+		} else {
+			c.Write(digest)
+		}
+		if i%3 != 0 {
+			c.Write(sequenceS)
+		}
+		if i%7 != 0 {
+			c.Write(sequenceP)
+		}
+		if i&1 != 0 {
+			c.Write(digest)
+		} else {
+			c.Write(sequenceP)
+		}
+		digest = c.Sum(nil)
+	}
+
+	var encoded strings.Builder
+	encode := func(b2, b1, b0 byte, n int) {
+		w := uint(b2)<<16 | uint(b1)<<8 | uint(b0)
+		for ; n > 0; n-- {
+			encoded.WriteByte(cryptCharacters[w&0x3f])
+			w >>= 6
+		}
+	}
+	for i := 0; i < 21; i++ {
+		encode(digest[i*22%63], digest[(i*22+21)%63], digest[(i*22+42)%63], 4)
+	}
+	encode(0, 0, digest[63], 2)
+
+	return "$6$" + salt + "$" + encoded.String()
+}
+
+func repeatDigest(digest []byte, length int) []byte {
+	sequence := make([]byte, 0, length)
+	for len(sequence) < length {
+		sequence = append(sequence, digest[:min(len(digest), length-len(sequence))]...)
+	}
+	return sequence
+}
@@ -0,0 +1,13 @@
+package credhubtest
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("sha512Crypt", func() {
+	It("matches the reference implementation", func() {
+		Expect(sha512Crypt("Hello world!", "saltstring")).To(Equal(
+			"$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1"))
+	})
+})
@@ -0,0 +1,39 @@
+package credhubtest
+
+import (
+	"net/http"
+)
+
+// interpolate replaces the credentials of each service binding in a VCAP_SERVICES document which hold a
+// "credhub-ref" with the current value of the referenced credential
+func (s *Server) interpolate(w http.ResponseWriter, r *http.Request) {
+	var services map[string][]map[string]interface{}
+	if !decodeBody(w, r, &services) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, bindings := range services {
+		for _, binding := range bindings {
+			credentials, ok := binding["credentials"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ref, ok := credentials["credhub-ref"].(string)
+			if !ok {
+				continue
+			}
+
+			c, ok := s.credentials[normalizeName(ref)]
+			if !ok {
+				writeError(w, http.StatusNotFound, errCredentialNotFound)
+				return
+			}
+			binding["credentials"] = c.latest().value
+		}
+	}
+
+	writeJSON(w, http.StatusOK, services)
+}
@@ -0,0 +1,139 @@
+package credhubtest
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/permissions"
+)
+
+const (
+	errPermissionExists    = "A permission entry for this actor and path already exists."
+	errPermissionActor     = "An actor and a path must be provided. Please validate your input and retry your request."
+	errPermissionOperation = "The provided operation is not supported. Valid values include read, write, delete, read_acl, and write_acl."
+)
+
+var permissionOperations = map[string]bool{
+	"read":      true,
+	"write":     true,
+	"delete":    true,
+	"read_acl":  true,
+	"write_acl": true,
+}
+
+// decodePermission decodes and validates the permission in the request body
+func decodePermission(w http.ResponseWriter, r *http.Request) (*permissions.Permission, bool) {
+	var permission permissions.Permission
+	if !decodeBody(w, r, &permission) {
+		return nil, false
+	}
+
+	if permission.Actor == "" || permission.Path == "" {
+		writeError(w, http.StatusBadRequest, errPermissionActor)
+		return nil, false
+	}
+	for _, operation := range permission.Operations {
+		if !permissionOperations[operation] {
+			writeError(w, http.StatusBadRequest, errPermissionOperation)
+			return nil, false
+		}
+	}
+
+	return &permission, true
+}
+
+func (s *Server) findPermission(path, actor string) *permissions.Permission {
+	for _, permission := range s.permissions {
+		if permission.Path == path && permission.Actor == actor {
+			return permission
+		}
+	}
+	return nil
+}
+
+func (s *Server) addPermission(w http.ResponseWriter, r *http.Request) {
+	permission, ok := decodePermission(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.findPermission(permission.Path, permission.Actor) != nil {
+		writeError(w, http.StatusConflict, errPermissionExists)
+		return
+	}
+
+	permission.UUID = newUUID()
+	s.permissions[permission.UUID] = permission
+
+	writeJSON(w, http.StatusCreated, permission)
+}
+
+func (s *Server) getPermissionByPathActor(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	permission := s.findPermission(query.Get("path"), query.Get("actor"))
+	if permission == nil {
+		writeError(w, http.StatusNotFound, errPermissionNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, permission)
+}
+
+func (s *Server) getPermission(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	permission, ok := s.permissions[r.PathValue("uuid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, errPermissionNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, permission)
+}
+
+func (s *Server) updatePermission(w http.ResponseWriter, r *http.Request) {
+	permission, ok := decodePermission(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uuid := r.PathValue("uuid")
+	if _, ok := s.permissions[uuid]; !ok {
+		writeError(w, http.StatusNotFound, errPermissionNotFound)
+		return
+	}
+	if existing := s.findPermission(permission.Path, permission.Actor); existing != nil && existing.UUID != uuid {
+		writeError(w, http.StatusConflict, errPermissionExists)
+		return
+	}
+
+	permission.UUID = uuid
+	s.permissions[uuid] = permission
+
+	writeJSON(w, http.StatusOK, permission)
+}
+
+func (s *Server) deletePermission(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uuid := r.PathValue("uuid")
+	permission, ok := s.permissions[uuid]
+	if !ok {
+		writeError(w, http.StatusNotFound, errPermissionNotFound)
+		return
+	}
+
+	delete(s.permissions, uuid)
+	writeJSON(w, http.StatusOK, permission)
+}
@@ -0,0 +1,173 @@
+// Package credhubtest provides an in-memory CredHub server for testing code which uses the credhub package.
+//
+// The server implements the credential, certificate, permission and interpolation APIs of CredHub, with
+// realistic generated values and the error responses of a real server. It does not check authorization:
+// every request is allowed, whatever its token.
+package credhubtest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+	"code.cloudfoundry.org/credhub-cli/credhub/permissions"
+)
+
+// Version is the CredHub version reported by the server
+const Version = "2.12.0"
+
+// Server is an in-memory CredHub server, listening on a local address
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	credentials map[string]*credential
+	permissions map[string]*permissions.Permission
+}
+
+// NewServer starts a Server listening for HTTP requests. Close it when done.
+func NewServer() *Server {
+	s := newServer()
+	s.Server = httptest.NewServer(s.handler())
+	return s
+}
+
+// NewTLSServer starts a Server listening for HTTPS requests. Close it when done.
+func NewTLSServer() *Server {
+	s := newServer()
+	s.Server = httptest.NewTLSServer(s.handler())
+	return s
+}
+
+func newServer() *Server {
+	return &Server{
+		credentials: map[string]*credential{},
+		permissions: map[string]*permissions.Permission{},
+	}
+}
+
+// CredHub returns a client for the server. The options are applied after those setting up the server's
+// CA certificate and authentication, which may be overridden.
+func (s *Server) CredHub(options ...credhub.Option) (*credhub.CredHub, error) {
+	defaults := []credhub.Option{credhub.Auth(auth.Noop)}
+	if s.Certificate() != nil {
+		ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+		defaults = append(defaults, credhub.CaCerts(string(ca)))
+	}
+
+	return credhub.New(s.URL, append(defaults, options...)...)
+}
+
+// Reset deletes all credentials and permissions
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.credentials = map[string]*credential{}
+	s.permissions = map[string]*permissions.Permission{}
+}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /info", s.info)
+	mux.HandleFunc("GET /version", s.version)
+	mux.HandleFunc("POST /oauth/token", s.token)
+	mux.HandleFunc("DELETE /oauth/token/revoke/{token}", func(w http.ResponseWriter, r *http.Request) {})
+
+	mux.HandleFunc("GET /api/v1/data", s.getData)
+	mux.HandleFunc("GET /api/v1/data/{id}", s.getDataByID)
+	mux.HandleFunc("PUT /api/v1/data", s.setData)
+	mux.HandleFunc("POST /api/v1/data", s.generateData)
+	mux.HandleFunc("DELETE /api/v1/data", s.deleteData)
+
+	mux.HandleFunc("GET /api/v1/certificates", s.getCertificates)
+	mux.HandleFunc("GET /api/v1/certificates/{$}", s.getCertificates)
+	mux.HandleFunc("POST /api/v1/certificates/{id}/regenerate", s.regenerateCertificate)
+	mux.HandleFunc("PUT /api/v1/certificates/{id}/update_transitional_version", s.updateTransitionalVersion)
+	mux.HandleFunc("GET /api/v1/certificates/{id}/versions", s.getCertificateVersions)
+	mux.HandleFunc("POST /api/v1/certificates/{id}/versions", s.createCertificateVersion)
+	mux.HandleFunc("DELETE /api/v1/certificates/{id}/versions/{versionID}", s.deleteCertificateVersion)
+	mux.HandleFunc("POST /api/v1/bulk-regenerate", s.bulkRegenerate)
+
+	mux.HandleFunc("POST /api/v2/permissions", s.addPermission)
+	mux.HandleFunc("GET /api/v2/permissions", s.getPermissionByPathActor)
+	mux.HandleFunc("GET /api/v2/permissions/{uuid}", s.getPermission)
+	mux.HandleFunc("PUT /api/v2/permissions/{uuid}", s.updatePermission)
+	mux.HandleFunc("DELETE /api/v2/permissions/{uuid}", s.deletePermission)
+
+	mux.HandleFunc("POST /api/v1/interpolate", s.interpolate)
+
+	return mux
+}
+
+func (s *Server) info(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"app":         map[string]string{"name": "CredHub", "version": Version},
+		"auth-server": map[string]string{"url": s.URL},
+	})
+}
+
+func (s *Server) version(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"version": Version})
+}
+
+// token issues an opaque token for any grant, so that clients authenticating with UAA can be used
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":  randomHex(16),
+		"refresh_token": randomHex(16),
+		"token_type":    "bearer",
+		"expires_in":    3600,
+	})
+}
+
+const (
+	errCredentialNotFound = "The request could not be completed because the credential does not exist or you do not have sufficient authorization."
+	errPermissionNotFound = "The request could not be completed because the permission does not exist or you do not have sufficient authorization."
+	errInvalidJSON        = "The request could not be fulfilled because the request path or body did not meet expectation. Please check the documentation for required formatting and retry your request."
+)
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, description string) {
+	writeJSON(w, status, map[string]string{"error": description})
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, body interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidJSON)
+		return false
+	}
+	return true
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -3,21 +3,52 @@ package credhub
 import (
 	"errors"
 	"fmt"
+	"net/http"
 )
 
 // Error provides errors for the CredHub client
+//
+// Errors returned for unsuccessful responses from the CredHub server also describe the response. They match
+// the sentinel error of their status code with errors.Is(), eg. ErrForbidden for a 403, and errors.As()
+// extracts their HTTPError or the typed error of their status code, eg. a *ForbiddenError.
 type Error struct {
 	Name        string `json:"error"`
 	Description string `json:"error_description"`
+
+	// StatusCode is the HTTP status code of the response, or 0 for errors which were not returned by the server
+	StatusCode int `json:"-"`
+
+	// Method and Path describe the request that failed
+	Method string `json:"-"`
+	Path   string `json:"-"`
+
+	// Body is the raw response body
+	Body []byte `json:"-"`
 }
 
 func (e *Error) Error() string {
-	if e.Description == "" {
+	switch {
+	case e.Description == "":
 		return e.Name
+	case e.Name == "":
+		return e.Description
 	}
 	return fmt.Sprintf("%s: %s", e.Name, e.Description)
 }
 
+// Is matches the sentinel error corresponding to the response status code, eg. ErrForbidden for a 403
+func (e *Error) Is(target error) bool {
+	return statusIs(e.StatusCode, target)
+}
+
+// As allows errors.As() to extract the HTTPError, or the typed error of the response status code
+func (e *Error) As(target interface{}) bool {
+	if e.StatusCode == 0 {
+		return false
+	}
+	return asHTTPError(&HTTPError{StatusCode: e.StatusCode, Method: e.Method, Path: e.Path, Body: e.Body, Err: e}, target)
+}
+
 func newCredhubError(name, description string) error {
 	return &Error{
 		Name:        name,
@@ -25,12 +56,161 @@ func newCredhubError(name, description string) error {
 	}
 }
 
+// NotFoundError is returned when the CredHub server responds with 404 Not Found
 type NotFoundError struct {
 	Description string `json:"error"`
+
+	// StatusCode, Method, Path and Body describe the response as they do for Error
+	StatusCode int    `json:"-"`
+	Method     string `json:"-"`
+	Path       string `json:"-"`
+	Body       []byte `json:"-"`
 }
 
 func (e *NotFoundError) Error() string {
 	return e.Description
 }
 
+// Is matches ErrNotFound
+func (e *NotFoundError) Is(target error) bool {
+	return statusIs(e.StatusCode, target)
+}
+
+// As allows errors.As() to extract the HTTPError
+func (e *NotFoundError) As(target interface{}) bool {
+	err := &Error{Name: e.Description, StatusCode: e.StatusCode, Method: e.Method, Path: e.Path, Body: e.Body}
+	return asHTTPError(&HTTPError{StatusCode: e.StatusCode, Method: e.Method, Path: e.Path, Body: e.Body, Err: err}, target)
+}
+
+// Sentinel errors matched by errors.Is() against errors returned for unsuccessful responses from the CredHub server.
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrServer       = errors.New("server error")
+)
+
+// HTTPError describes an unsuccessful response from the CredHub server.
+//
+// The client returns an *Error, or a *NotFoundError for 404 Not Found. errors.As() with a **HTTPError
+// target extracts the HTTPError from either, and with a target of one of the more specific
+// BadRequestError, UnauthorizedError, ForbiddenError, ConflictError or ServerError types, which all
+// embed HTTPError, it extracts the one matching the status code.
+type HTTPError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int `json:"-"`
+
+	// Method and Path describe the request that failed
+	Method string `json:"-"`
+	Path   string `json:"-"`
+
+	// Body is the raw response body
+	Body []byte `json:"-"`
+
+	// Err is the error decoded from the response body
+	Err *Error `json:"-"`
+}
+
+func (e *HTTPError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("%s %s: %d %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the error decoded from the response body
+func (e *HTTPError) Unwrap() error {
+	if e.Err == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// Is matches the sentinel error corresponding to the response status code, eg. ErrForbidden for a 403
+func (e *HTTPError) Is(target error) bool {
+	return statusIs(e.StatusCode, target)
+}
+
+// BadRequestError describes a response with 400 Bad Request
+type BadRequestError struct {
+	HTTPError
+}
+
+// UnauthorizedError describes a response with 401 Unauthorized
+type UnauthorizedError struct {
+	HTTPError
+}
+
+// ForbiddenError describes a response with 403 Forbidden
+type ForbiddenError struct {
+	HTTPError
+}
+
+// ConflictError describes a response with 409 Conflict
+type ConflictError struct {
+	HTTPError
+}
+
+// ServerError describes a response with a 5xx status code
+type ServerError struct {
+	HTTPError
+}
+
+func statusIs(statusCode int, target error) bool {
+	switch {
+	case statusCode == http.StatusBadRequest:
+		return target == ErrBadRequest
+	case statusCode == http.StatusUnauthorized:
+		return target == ErrUnauthorized
+	case statusCode == http.StatusForbidden:
+		return target == ErrForbidden
+	case statusCode == http.StatusNotFound:
+		return target == ErrNotFound
+	case statusCode == http.StatusConflict:
+		return target == ErrConflict
+	case statusCode >= 500:
+		return target == ErrServer
+	default:
+		return false
+	}
+}
+
+// asHTTPError sets the target to the HTTPError, or to the typed error of its status code
+func asHTTPError(e *HTTPError, target interface{}) bool {
+	switch t := target.(type) {
+	case **HTTPError:
+		*t = e
+	case **BadRequestError:
+		if e.StatusCode != http.StatusBadRequest {
+			return false
+		}
+		*t = &BadRequestError{*e}
+	case **UnauthorizedError:
+		if e.StatusCode != http.StatusUnauthorized {
+			return false
+		}
+		*t = &UnauthorizedError{*e}
+	case **ForbiddenError:
+		if e.StatusCode != http.StatusForbidden {
+			return false
+		}
+		*t = &ForbiddenError{*e}
+	case **ConflictError:
+		if e.StatusCode != http.StatusConflict {
+			return false
+		}
+		*t = &ConflictError{*e}
+	case **ServerError:
+		if e.StatusCode < 500 {
+			return false
+		}
+		*t = &ServerError{*e}
+	default:
+		return false
+	}
+	return true
+}
+
 var ServerDoesNotSupportMetadataError = errors.New("the server does not support credential metadata, requires >= 2.6.x")
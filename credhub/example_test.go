@@ -6,6 +6,7 @@ import (
 
 	"code.cloudfoundry.org/credhub-cli/credhub"
 	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
 	"code.cloudfoundry.org/credhub-cli/credhub/credentials/generate"
 )
 
@@ -145,3 +146,32 @@ func Example() {
 		// /my-certificates/the-cert
 	}
 }
+
+// auditingReader logs the name of each credential read through it
+type auditingReader struct {
+	credhub.Reader
+}
+
+func (r auditingReader) GetLatestPassword(name string) (credentials.Password, error) {
+	fmt.Println("Reading ", name)
+	return r.Reader.GetLatestPassword(name)
+}
+
+func ExampleReader() {
+	_ = func() {
+		ch, _ := credhub.New("https://example.com")
+
+		// Code which depends on credhub.Reader can be given a decorator or a fake instead of the client
+		var reader credhub.Reader = auditingReader{Reader: ch}
+
+		password, err := reader.GetLatestPassword("/my/password")
+		if err != nil {
+			panic("password not found")
+		}
+
+		fmt.Println("My password: ", password.Value)
+		// Sample Output:
+		// Reading /my/password
+		// My password: random-password
+	}
+}
@@ -0,0 +1,31 @@
+package credhub
+
+import "net/http"
+
+// Interceptor wraps the http.RoundTripper that sends requests to the CredHub and auth servers.
+//
+// An Interceptor can inspect or modify requests before calling next, and inspect, replace or
+// fail responses after it returns, eg. to add custom headers or request IDs, record metrics,
+// audit calls or inject faults in tests. Requests seen by an Interceptor already carry the
+// authentication added by the auth Strategy.
+type Interceptor func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc allows a function to be used as an http.RoundTripper, eg. when writing an Interceptor.
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req)
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func intercept(transport http.RoundTripper, interceptors []Interceptor) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		transport = interceptors[i](transport)
+	}
+
+	return transport
+}
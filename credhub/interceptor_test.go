@@ -0,0 +1,99 @@
+package credhub_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Interceptors", func() {
+	var (
+		server   *httptest.Server
+		requests []*http.Request
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r)
+			w.Write([]byte(`{"app":{"name":"CredHub","version":"2.6.0"},"auth-server":{"url":"https://uaa.example.com"}}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	header := func(name, value string) Interceptor {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				req.Header.Add(name, value)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	It("intercepts unauthenticated requests", func() {
+		ch, _ := New(server.URL, Interceptors(header("X-Request-Id", "some-request-id")))
+
+		_, err := ch.Info()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Header.Get("X-Request-Id")).To(Equal("some-request-id"))
+	})
+
+	It("intercepts authenticated requests after authentication is added", func() {
+		var authorization string
+		recordAuthorization := func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				authorization = req.Header.Get("Authorization")
+				return next.RoundTrip(req)
+			})
+		}
+
+		ch, _ := New(server.URL,
+			AuthURL("https://uaa.example.com"),
+			Auth(auth.Uaa("client-id", "client-secret", "", "", "some-access-token", "", true)),
+			Interceptors(recordAuthorization),
+		)
+
+		_, err := ch.Request(http.MethodGet, "/api/v1/data", nil, nil, true)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(authorization).To(Equal("Bearer some-access-token"))
+	})
+
+	It("runs the first interceptor outermost", func() {
+		ch, _ := New(server.URL,
+			Interceptors(header("X-Order", "first")),
+			Interceptors(header("X-Order", "second"), header("X-Order", "third")),
+		)
+
+		_, err := ch.Info()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests[0].Header.Values("X-Order")).To(Equal([]string{"first", "second", "third"}))
+	})
+
+	It("allows interceptors to fail requests without sending them", func() {
+		injectFault := func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				return nil, errors.New("injected fault")
+			})
+		}
+
+		ch, _ := New(server.URL, Interceptors(injectFault))
+
+		_, err := ch.Info()
+
+		Expect(err).To(MatchError(ContainSubstring("injected fault")))
+		Expect(requests).To(BeEmpty())
+	})
+})
@@ -0,0 +1,107 @@
+package credhub
+
+import (
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/generate"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/values"
+	"code.cloudfoundry.org/credhub-cli/credhub/permissions"
+	"code.cloudfoundry.org/credhub-cli/credhub/server"
+	"github.com/hashicorp/go-version"
+)
+
+// Reader gets and finds credentials
+//
+// The CredHub struct conforms to this interface
+type Reader interface {
+	GetById(id string) (credentials.Credential, error)
+	GetAllVersions(name string) ([]credentials.Credential, error)
+	GetLatestVersion(name string) (credentials.Credential, error)
+	GetNVersions(name string, numberOfVersions int) ([]credentials.Credential, error)
+	GetLatestValue(name string) (credentials.Value, error)
+	GetLatestJSON(name string) (credentials.JSON, error)
+	GetLatestPassword(name string) (credentials.Password, error)
+	GetLatestUser(name string) (credentials.User, error)
+	GetLatestCertificate(name string) (credentials.Certificate, error)
+	GetLatestRSA(name string) (credentials.RSA, error)
+	GetLatestSSH(name string) (credentials.SSH, error)
+	FindByPartialName(nameLike string) (credentials.FindResults, error)
+	FindByPath(path string) (credentials.FindResults, error)
+	InterpolateString(vcapServicesBody string) (string, error)
+}
+
+// Writer sets and deletes credentials
+//
+// The CredHub struct conforms to this interface
+type Writer interface {
+	SetValue(name string, value values.Value, options ...SetOption) (credentials.Value, error)
+	SetJSON(name string, value values.JSON, options ...SetOption) (credentials.JSON, error)
+	SetPassword(name string, value values.Password, options ...SetOption) (credentials.Password, error)
+	SetUser(name string, value values.User, options ...SetOption) (credentials.User, error)
+	SetCertificate(name string, value values.Certificate, options ...SetOption) (credentials.Certificate, error)
+	SetRSA(name string, value values.RSA, options ...SetOption) (credentials.RSA, error)
+	SetSSH(name string, value values.SSH, options ...SetOption) (credentials.SSH, error)
+	SetCredential(name, credType string, value interface{}, options ...SetOption) (credentials.Credential, error)
+	Delete(name string) error
+}
+
+// Generator generates and regenerates credentials
+//
+// The CredHub struct conforms to this interface
+type Generator interface {
+	GeneratePassword(name string, gen generate.Password, overwrite Mode) (credentials.Password, error)
+	GenerateUser(name string, gen generate.User, overwrite Mode) (credentials.User, error)
+	GenerateCertificate(name string, gen generate.Certificate, overwrite Mode) (credentials.Certificate, error)
+	GenerateRSA(name string, gen generate.RSA, overwrite Mode) (credentials.RSA, error)
+	GenerateSSH(name string, gen generate.SSH, overwrite Mode) (credentials.SSH, error)
+	GenerateCredential(name, credType string, gen interface{}, overwrite Mode, options ...GenerateOption) (credentials.Credential, error)
+	Regenerate(name string, options ...RegenerateOption) (credentials.Credential, error)
+}
+
+// Permissions manages the permissions of actors on credential paths
+//
+// The CredHub struct conforms to this interface
+type Permissions interface {
+	GetPermissions(name string) ([]permissions.V1_Permission, error)
+	GetPermissionByUUID(uuid string) (*permissions.Permission, error)
+	GetPermissionByPathActor(path string, actor string) (*permissions.Permission, error)
+	AddPermission(path string, actor string, ops []string) (*permissions.Permission, error)
+	UpdatePermission(uuid string, path string, actor string, ops []string) (*permissions.Permission, error)
+	DeletePermission(uuid string) (*permissions.Permission, error)
+}
+
+// Certificates gets the metadata of certificates, regenerates the certificates signed by a CA and manages the
+// versions of certificates
+//
+// The CredHub struct conforms to this interface
+type Certificates interface {
+	GetAllCertificatesMetadata() ([]credentials.CertificateMetadata, error)
+	GetCertificateMetadataByName(name string) (credentials.CertificateMetadata, error)
+	BulkRegenerate(signedBy string) (credentials.BulkRegenerateResults, error)
+	RegenerateCertificate(certificateID string, setAsTransitional bool) (credentials.Certificate, error)
+	UpdateTransitionalVersion(certificateID, versionID string) ([]credentials.Certificate, error)
+	GetCertificateVersions(certificateID string, current bool) ([]credentials.CertificateVersion, error)
+	CreateCertificateVersion(certificateID string, value values.Certificate, transitional bool) (credentials.CertificateVersion, error)
+	DeleteCertificateVersion(certificateID, versionID string) (credentials.CertificateVersion, error)
+}
+
+// Client is everything the CredHub struct provides to talk to a CredHub server. Depend on it, or on the
+// narrower interfaces it is made of, to substitute fakes or decorators for the CredHub struct.
+//
+// The CredHub struct conforms to this interface
+type Client interface {
+	Reader
+	Writer
+	Generator
+	Permissions
+	Certificates
+
+	Info() (*server.Info, error)
+	AuthURL() (string, error)
+	ServerVersion() (*version.Version, error)
+	Request(method string, pathStr string, query url.Values, body interface{}, checkServerErr bool) (*http.Response, error)
+}
+
+var _ Client = new(CredHub)
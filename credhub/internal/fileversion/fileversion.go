@@ -0,0 +1,24 @@
+// Change detection for files which the CredHub client reloads when they are replaced on disk
+package fileversion
+
+import (
+	"os"
+	"time"
+)
+
+// Version identifies the contents of a file by its modification time and size, so that a changed file
+// can be reloaded without reading it on every request
+type Version struct {
+	modTime time.Time
+	size    int64
+}
+
+// Stat returns the current Version of the named file
+func Stat(name string) (Version, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return Version{}, err
+	}
+
+	return Version{modTime: info.ModTime(), size: info.Size()}, nil
+}
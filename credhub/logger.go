@@ -0,0 +1,209 @@
+package credhub
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"regexp"
+)
+
+const redacted = "<redacted>"
+
+// Headers whose values are never logged
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// JSON fields and form parameters whose values are never logged
+var sensitiveFields = map[string]bool{
+	"value":         true,
+	"password":      true,
+	"private_key":   true,
+	"access_token":  true,
+	"refresh_token": true,
+	"id_token":      true,
+	"client_secret": true,
+	"passcode":      true,
+}
+
+var privateKeyPattern = regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`)
+
+func (ch *CredHub) log() *slog.Logger {
+	if ch.logger != nil {
+		return ch.logger
+	}
+
+	if os.Getenv("CREDHUB_DEBUG") == "true" {
+		return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+
+	return slog.New(slog.DiscardHandler)
+}
+
+// loggingTransport logs requests and responses at debug level, with secrets redacted
+type loggingTransport struct {
+	logger *slog.Logger
+	next   http.RoundTripper
+}
+
+func newLoggingTransport(logger *slog.Logger, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &loggingTransport{logger: logger, next: next}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if !t.logger.Enabled(ctx, slog.LevelDebug) {
+		return t.next.RoundTrip(req)
+	}
+
+	t.logger.DebugContext(ctx, "sending request", "method", req.Method, "url", redactURL(req.URL), "dump", dumpRequest(req))
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.logger.DebugContext(ctx, "request failed", "method", req.Method, "url", redactURL(req.URL), "error", err)
+		return resp, err
+	}
+
+	t.logger.DebugContext(ctx, "received response", "method", req.Method, "url", redactURL(req.URL), "status", resp.StatusCode, "dump", dumpResponse(resp))
+
+	return resp, nil
+}
+
+func dumpRequest(req *http.Request) string {
+	clone := req.Clone(req.Context())
+	clone.Header = redactHeaders(req.Header)
+	clone.URL = redactURL(req.URL)
+	clone.Body = nil
+	clone.ContentLength = 0
+
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return "An error occurred during request dump: " + err.Error()
+		}
+		defer body.Close()
+
+		buf, err := io.ReadAll(body)
+		if err != nil {
+			return "An error occurred during request dump: " + err.Error()
+		}
+
+		redactedBody := redactBody(req.Header.Get("Content-Type"), buf)
+		clone.Body = io.NopCloser(bytes.NewReader(redactedBody))
+		clone.ContentLength = int64(len(redactedBody))
+	}
+
+	dump, err := httputil.DumpRequestOut(clone, true)
+	if err != nil {
+		return "An error occurred during request dump: " + err.Error()
+	}
+	return string(dump)
+}
+
+func dumpResponse(resp *http.Response) string {
+	buf, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(buf))
+	if err != nil {
+		return "An error occurred during response dump: " + err.Error()
+	}
+
+	redactedBody := redactBody(resp.Header.Get("Content-Type"), buf)
+
+	clone := *resp
+	clone.Header = redactHeaders(resp.Header)
+	clone.Body = io.NopCloser(bytes.NewReader(redactedBody))
+	clone.ContentLength = int64(len(redactedBody))
+	clone.TransferEncoding = nil
+
+	dump, err := httputil.DumpResponse(&clone, true)
+	if err != nil {
+		return "An error occurred during response dump: " + err.Error()
+	}
+	return string(dump)
+}
+
+func redactHeaders(header http.Header) http.Header {
+	clone := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if clone.Get(name) != "" {
+			clone.Set(name, redacted)
+		}
+	}
+	return clone
+}
+
+func redactURL(u *url.URL) *url.URL {
+	clone := *u
+	if clone.User != nil {
+		clone.User = url.User(redacted)
+	}
+	query := clone.Query()
+	for key := range query {
+		if sensitiveFields[key] {
+			query.Set(key, redacted)
+		}
+	}
+	clone.RawQuery = query.Encode()
+	return &clone
+}
+
+// redactBody masks sensitive fields in JSON and form encoded bodies, and any PEM encoded private keys
+func redactBody(contentType string, body []byte) []byte {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	if mediaType == "application/x-www-form-urlencoded" {
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			for key := range form {
+				if sensitiveFields[key] {
+					form.Set(key, redacted)
+				}
+			}
+			return []byte(form.Encode())
+		}
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(redactJSON(parsed)); err == nil {
+			return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+		}
+	}
+
+	return privateKeyPattern.ReplaceAll(body, []byte(redacted))
+}
+
+func redactJSON(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			_, isObject := field.(map[string]interface{})
+			if sensitiveFields[key] || (key == "credentials" && isObject) {
+				// credentials objects hold the values interpolated into VCAP_SERVICES
+				value[key] = redacted
+			} else {
+				value[key] = redactJSON(field)
+			}
+		}
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redactJSON(item)
+		}
+		return value
+	case string:
+		return privateKeyPattern.ReplaceAllString(value, redacted)
+	default:
+		return value
+	}
+}
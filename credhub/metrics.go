@@ -0,0 +1,57 @@
+package credhub
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+	"code.cloudfoundry.org/credhub-cli/credhub/metrics"
+)
+
+// Path segments followed by the ID of one of their members, eg. /api/v1/data/{id}
+var collectionSegments = map[string]bool{
+	"data":         true,
+	"permissions":  true,
+	"certificates": true,
+	"versions":     true,
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Metrics returns the Recorder that requests made by this client are reported to. See the Metrics() option.
+func (ch *CredHub) Metrics() metrics.Recorder {
+	if ch.metrics != nil {
+		return ch.metrics
+	}
+	return metrics.Noop
+}
+
+func (ch *CredHub) observeRequest(method, pathStr string, start time.Time, resp *http.Response, err error) {
+	statusClass := metrics.StatusError
+	if err == nil {
+		statusClass = metrics.StatusClass(resp.StatusCode)
+	}
+
+	ch.Metrics().ObserveRequest(endpointTemplate(pathStr), method, statusClass, time.Since(start))
+}
+
+// endpointTemplate replaces the IDs in pathStr with a placeholder, so that requests
+// to the same endpoint are reported together
+func endpointTemplate(pathStr string) string {
+	segments := strings.Split(pathStr, "/")
+
+	for i, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		if (i > 0 && collectionSegments[segments[i-1]]) || uuidPattern.MatchString(segment) {
+			segments[i] = "{id}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+var _ auth.MetricsConfig = new(CredHub)
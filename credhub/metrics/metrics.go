@@ -0,0 +1,166 @@
+// Metrics the CredHub client records for requests and token refreshes
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status classes of observed requests
+const (
+	Status1xx   = "1xx"
+	Status2xx   = "2xx"
+	Status3xx   = "3xx"
+	Status4xx   = "4xx"
+	Status5xx   = "5xx"
+	StatusError = "error" // the request failed without a response
+)
+
+// Outcomes of observed token refreshes
+const (
+	RefreshSuccess = "success"
+	RefreshFailure = "failure"
+)
+
+// Recorder receives measurements from the CredHub client.
+//
+// Implementations must be safe for concurrent use. They typically forward measurements
+// to counters and histograms of a metrics library, labelled with the given arguments.
+type Recorder interface {
+	// ObserveRequest records a request to the CredHub server.
+	//
+	// endpoint is the request path with IDs replaced by a placeholder, eg. /api/v1/data/{id}.
+	// statusClass is one of the Status* constants.
+	ObserveRequest(endpoint, method, statusClass string, duration time.Duration)
+
+	// ObserveRefresh records an attempt of the auth Strategy to obtain a new access token.
+	// outcome is RefreshSuccess or RefreshFailure.
+	ObserveRefresh(outcome string, duration time.Duration)
+}
+
+// Noop discards all measurements
+var Noop Recorder = noop{}
+
+type noop struct{}
+
+func (noop) ObserveRequest(string, string, string, time.Duration) {}
+func (noop) ObserveRefresh(string, time.Duration)                 {}
+
+// StatusClass returns the status class of an HTTP status code, eg. "4xx" for 404
+func StatusClass(statusCode int) string {
+	switch {
+	case statusCode >= 100 && statusCode < 200:
+		return Status1xx
+	case statusCode >= 200 && statusCode < 300:
+		return Status2xx
+	case statusCode >= 300 && statusCode < 400:
+		return Status3xx
+	case statusCode >= 400 && statusCode < 500:
+		return Status4xx
+	case statusCode >= 500 && statusCode < 600:
+		return Status5xx
+	default:
+		return StatusError
+	}
+}
+
+// RequestKey identifies a series of requests observed by InMemory
+type RequestKey struct {
+	Endpoint    string
+	Method      string
+	StatusClass string
+}
+
+// InMemory keeps all measurements in memory. It is intended for tests.
+type InMemory struct {
+	mu        sync.Mutex
+	requests  map[RequestKey][]time.Duration
+	refreshes map[string][]time.Duration
+}
+
+// NewInMemory returns an empty InMemory recorder
+func NewInMemory() *InMemory {
+	return &InMemory{
+		requests:  map[RequestKey][]time.Duration{},
+		refreshes: map[string][]time.Duration{},
+	}
+}
+
+func (m *InMemory) ObserveRequest(endpoint, method, statusClass string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := RequestKey{Endpoint: endpoint, Method: method, StatusClass: statusClass}
+	m.requests[key] = append(m.requests[key], duration)
+}
+
+func (m *InMemory) ObserveRefresh(outcome string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.refreshes[outcome] = append(m.refreshes[outcome], duration)
+}
+
+// RequestKeys returns the keys of all observed request series, sorted by endpoint, method and status class
+func (m *InMemory) RequestKeys() []RequestKey {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]RequestKey, 0, len(m.requests))
+	for key := range m.requests {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Endpoint != keys[j].Endpoint {
+			return keys[i].Endpoint < keys[j].Endpoint
+		}
+		if keys[i].Method != keys[j].Method {
+			return keys[i].Method < keys[j].Method
+		}
+		return keys[i].StatusClass < keys[j].StatusClass
+	})
+
+	return keys
+}
+
+// RequestCount returns the number of requests observed for key
+func (m *InMemory) RequestCount(key RequestKey) int {
+	return len(m.RequestDurations(key))
+}
+
+// RequestDurations returns the durations of the requests observed for key, in observation order
+func (m *InMemory) RequestDurations(key RequestKey) []time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]time.Duration{}, m.requests[key]...)
+}
+
+// RefreshCount returns the number of token refreshes observed with the given outcome
+func (m *InMemory) RefreshCount(outcome string) int {
+	return len(m.RefreshDurations(outcome))
+}
+
+// RefreshDurations returns the durations of the token refreshes observed with the given outcome
+func (m *InMemory) RefreshDurations(outcome string) []time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]time.Duration{}, m.refreshes[outcome]...)
+}
+
+// Reset discards all measurements
+func (m *InMemory) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests = map[RequestKey][]time.Duration{}
+	m.refreshes = map[string][]time.Duration{}
+}
+
+var (
+	_ Recorder = Noop
+	_ Recorder = new(InMemory)
+)
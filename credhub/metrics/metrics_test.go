@@ -0,0 +1,81 @@
+package metrics_test
+
+import (
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/metrics"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Metrics", func() {
+	DescribeTable("StatusClass()",
+		func(statusCode int, expected string) {
+			Expect(metrics.StatusClass(statusCode)).To(Equal(expected))
+		},
+		Entry("100", 100, metrics.Status1xx),
+		Entry("200", 200, metrics.Status2xx),
+		Entry("302", 302, metrics.Status3xx),
+		Entry("404", 404, metrics.Status4xx),
+		Entry("503", 503, metrics.Status5xx),
+		Entry("an invalid status code", 0, metrics.StatusError),
+	)
+
+	Describe("InMemory", func() {
+		var recorder *metrics.InMemory
+
+		BeforeEach(func() {
+			recorder = metrics.NewInMemory()
+		})
+
+		It("records requests by endpoint, method and status class", func() {
+			recorder.ObserveRequest("/api/v1/data", "GET", metrics.Status2xx, time.Second)
+			recorder.ObserveRequest("/api/v1/data", "GET", metrics.Status2xx, 2*time.Second)
+			recorder.ObserveRequest("/api/v1/data/{id}", "GET", metrics.Status4xx, time.Millisecond)
+
+			key := metrics.RequestKey{Endpoint: "/api/v1/data", Method: "GET", StatusClass: metrics.Status2xx}
+			Expect(recorder.RequestCount(key)).To(Equal(2))
+			Expect(recorder.RequestDurations(key)).To(Equal([]time.Duration{time.Second, 2 * time.Second}))
+			Expect(recorder.RequestKeys()).To(Equal([]metrics.RequestKey{
+				key,
+				{Endpoint: "/api/v1/data/{id}", Method: "GET", StatusClass: metrics.Status4xx},
+			}))
+		})
+
+		It("records refreshes by outcome", func() {
+			recorder.ObserveRefresh(metrics.RefreshSuccess, time.Second)
+			recorder.ObserveRefresh(metrics.RefreshFailure, time.Minute)
+
+			Expect(recorder.RefreshCount(metrics.RefreshSuccess)).To(Equal(1))
+			Expect(recorder.RefreshDurations(metrics.RefreshFailure)).To(Equal([]time.Duration{time.Minute}))
+		})
+
+		It("discards all measurements on Reset()", func() {
+			recorder.ObserveRequest("/info", "GET", metrics.Status2xx, time.Second)
+			recorder.ObserveRefresh(metrics.RefreshSuccess, time.Second)
+
+			recorder.Reset()
+
+			Expect(recorder.RequestKeys()).To(BeEmpty())
+			Expect(recorder.RefreshCount(metrics.RefreshSuccess)).To(Equal(0))
+		})
+
+		It("is safe for concurrent use", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					recorder.ObserveRequest("/info", "GET", metrics.Status2xx, time.Second)
+					recorder.ObserveRefresh(metrics.RefreshSuccess, time.Second)
+				}()
+			}
+			wg.Wait()
+
+			Expect(recorder.RequestCount(metrics.RequestKey{Endpoint: "/info", Method: "GET", StatusClass: metrics.Status2xx})).To(Equal(10))
+			Expect(recorder.RefreshCount(metrics.RefreshSuccess)).To(Equal(10))
+		})
+	})
+})
@@ -0,0 +1,102 @@
+package credhub_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	. "code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+	"code.cloudfoundry.org/credhub-cli/credhub/metrics"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Metrics", func() {
+	var (
+		server   *httptest.Server
+		recorder *metrics.InMemory
+		status   int
+	)
+
+	BeforeEach(func() {
+		recorder = metrics.NewInMemory()
+		status = http.StatusOK
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			w.Write([]byte(`{}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	key := func(endpoint, method, statusClass string) metrics.RequestKey {
+		return metrics.RequestKey{Endpoint: endpoint, Method: method, StatusClass: statusClass}
+	}
+
+	It("discards measurements by default", func() {
+		ch, _ := New(server.URL)
+
+		Expect(ch.Metrics()).To(Equal(metrics.Noop))
+	})
+
+	DescribeTable("reports requests by endpoint template",
+		func(method, path, endpoint string) {
+			ch, _ := New(server.URL, Metrics(recorder))
+
+			_, err := ch.Request(method, path, url.Values{"name": []string{"/some-name"}}, nil, false)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.RequestKeys()).To(ConsistOf(key(endpoint, method, metrics.Status2xx)))
+		},
+		Entry("credentials", http.MethodGet, "/api/v1/data", "/api/v1/data"),
+		Entry("a credential by ID", http.MethodGet, "/api/v1/data/some-id", "/api/v1/data/{id}"),
+		Entry("a permission by UUID", http.MethodDelete, "/api/v2/permissions/6b3b4cfa-58b4-4b8b-9f5c-4d6b9c2f1e1a", "/api/v2/permissions/{id}"),
+		Entry("certificates", http.MethodGet, "/api/v1/certificates/", "/api/v1/certificates/"),
+		Entry("a certificate version", http.MethodDelete, "/api/v1/certificates/some-id/versions/some-version-id", "/api/v1/certificates/{id}/versions/{id}"),
+		Entry("a UUID outside of a known collection", http.MethodGet, "/some/6b3b4cfa-58b4-4b8b-9f5c-4d6b9c2f1e1a/path", "/some/{id}/path"),
+	)
+
+	It("reports the status class of unsuccessful responses", func() {
+		status = http.StatusNotFound
+		ch, _ := New(server.URL, Metrics(recorder))
+
+		_, err := ch.Request(http.MethodGet, "/api/v1/data", nil, nil, true)
+
+		Expect(err).To(HaveOccurred())
+		Expect(recorder.RequestCount(key("/api/v1/data", http.MethodGet, metrics.Status4xx))).To(Equal(1))
+	})
+
+	It("reports every attempt of a retried request", func() {
+		status = http.StatusServiceUnavailable
+		ch, _ := New(server.URL, Metrics(recorder), Retry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}))
+
+		_, err := ch.Request(http.MethodGet, "/api/v1/data", nil, nil, false)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.RequestCount(key("/api/v1/data", http.MethodGet, metrics.Status5xx))).To(Equal(3))
+	})
+
+	It("reports requests which fail without a response", func() {
+		ch, _ := New(server.URL, Metrics(recorder))
+		server.Close()
+
+		_, err := ch.Request(http.MethodGet, "/api/v1/data", nil, nil, true)
+
+		Expect(err).To(HaveOccurred())
+		Expect(recorder.RequestCount(key("/api/v1/data", http.MethodGet, metrics.StatusError))).To(Equal(1))
+	})
+
+	It("provides the recorder to the OAuthStrategy", func() {
+		ch, err := New(server.URL, Metrics(recorder), AuthURL("https://uaa.example.com"), Auth(auth.UaaClientCredentials("some-client", "some-secret")))
+		Expect(err).NotTo(HaveOccurred())
+
+		oauth, ok := ch.Auth.(*auth.OAuthStrategy)
+		Expect(ok).To(BeTrue())
+		Expect(oauth.Metrics).To(BeIdenticalTo(recorder))
+	})
+})
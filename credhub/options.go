@@ -1,14 +1,15 @@
 package credhub
 
 import (
-	"crypto/tls"
 	"crypto/x509"
 	"errors"
+	"log/slog"
 	"net/url"
 	"runtime"
 	"time"
 
 	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+	"code.cloudfoundry.org/credhub-cli/credhub/metrics"
 )
 
 // Option can be provided to New() to specify additional parameters for
@@ -77,13 +78,17 @@ func SkipTLSValidation(skipTLSvalidation bool) Option {
 }
 
 // ClientCert will use a certificate for authentication
+//
+// The certificate and key files are read again when they change on disk, so that new TLS
+// connections present the rotated certificate. Use it with auth.MutualTLS to authenticate
+// with the client certificate alone.
 func ClientCert(certificate, key string) Option {
 	return func(c *CredHub) error {
-		cert, err := tls.LoadX509KeyPair(certificate, key)
+		cert, err := loadClientCertificate(certificate, key)
 		if err != nil {
 			return err
 		}
-		c.clientCertificate = &cert
+		c.clientCertificate = cert
 
 		return nil
 	}
@@ -97,6 +102,66 @@ func SetHttpTimeout(timeout *time.Duration) Option {
 	}
 }
 
+// Retry will retry requests that fail with a transient error according to the policy.
+//
+// Unset InitialBackoff and MaxBackoff fields take their value from DefaultRetryPolicy.
+func Retry(policy RetryPolicy) Option {
+	return func(c *CredHub) error {
+		if policy.InitialBackoff <= 0 {
+			policy.InitialBackoff = DefaultRetryPolicy.InitialBackoff
+		}
+		if policy.MaxBackoff <= 0 {
+			policy.MaxBackoff = DefaultRetryPolicy.MaxBackoff
+		}
+		c.retryPolicy = policy
+		return nil
+	}
+}
+
+// Logger will send diagnostic messages about requests to logger.
+//
+// Requests to the CredHub and auth servers, and their responses, are logged at debug level.
+// Authorization headers, credential values, private keys and tokens are redacted.
+// Retries are logged at warn level.
+//
+// Without a Logger, requests are logged to stderr when the CREDHUB_DEBUG environment variable is "true".
+// A nil logger keeps this default.
+func Logger(logger *slog.Logger) Option {
+	return func(c *CredHub) error {
+		if logger != nil {
+			c.logger = logger
+		}
+		return nil
+	}
+}
+
+// Interceptors installs a chain of interceptors around the transport of Client().
+//
+// Client() is used for authenticated requests to the CredHub server as well as unauthenticated
+// ones, such as /info and the requests made to the auth server, so the interceptors see them all.
+// The first interceptor is the outermost one: it sees requests first and responses last.
+// Providing this option more than once appends to the chain.
+func Interceptors(interceptors ...Interceptor) Option {
+	return func(c *CredHub) error {
+		c.interceptors = append(c.interceptors, interceptors...)
+		return nil
+	}
+}
+
+// Metrics will report every request to the CredHub server, and every token refresh of the
+// OAuthStrategy, to recorder. A nil recorder keeps the default, which discards them.
+//
+// Requests are reported once per attempt, labelled with the endpoint (with IDs replaced by
+// a placeholder), the method and the status class of the response.
+func Metrics(recorder metrics.Recorder) Option {
+	return func(c *CredHub) error {
+		if recorder != nil {
+			c.metrics = recorder
+		}
+		return nil
+	}
+}
+
 func ServerVersion(version string) Option {
 	return func(c *CredHub) error {
 		c.cachedServerVersion = version
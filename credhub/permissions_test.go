@@ -2,6 +2,7 @@ package credhub_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -154,6 +155,7 @@ var _ = Describe("Permissions", func() {
 
 				_, err := ch.GetPermissionByPathActor("/path", "some-actor")
 				Expect(err).To(BeAssignableToTypeOf(&Error{}))
+				Expect(errors.Is(err, ErrServer)).To(BeTrue())
 			})
 		})
 	})
@@ -4,12 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
-	"os"
+	"time"
 )
 
 // Request sends an authenticated request to the CredHub server.
@@ -21,6 +19,8 @@ import (
 //
 // Use Request() directly to send authenticated requests to the CredHub server.
 // For unauthenticated requests (eg. /health), use Config.Client() instead.
+//
+// The request is bound to the client's Context(). Use WithContext() to cancel it or set a deadline.
 func (ch *CredHub) Request(method string, pathStr string, query url.Values, body interface{}, checkServerErr bool) (*http.Response, error) {
 	return ch.request(ch.Auth, method, pathStr, query, body, checkServerErr)
 }
@@ -34,38 +34,47 @@ func (ch *CredHub) request(client requester, method string, pathStr string, quer
 	u.Path = pathStr
 	u.RawQuery = query.Encode()
 
-	var req *http.Request
-
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
 
-	if body != nil {
-		req, err = http.NewRequest(method, u.String(), bytes.NewReader(jsonBody))
-	} else {
-		req, err = http.NewRequest(method, u.String(), nil)
-	}
-	if err != nil {
-		return nil, err
-	}
+	var resp *http.Response
 
-	req.Header.Set("Content-Type", "application/json")
+	for attempt := 1; ; attempt++ {
+		var req *http.Request
 
-	if os.Getenv("CREDHUB_DEBUG") == "true" {
-		dumpRequest(req)
-	}
+		if body != nil {
+			req, err = http.NewRequestWithContext(ch.Context(), method, u.String(), bytes.NewReader(jsonBody))
+		} else {
+			req, err = http.NewRequestWithContext(ch.Context(), method, u.String(), nil)
+		}
+		if err != nil {
+			return nil, err
+		}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		if os.Getenv("CREDHUB_DEBUG") == "true" {
-			fmt.Printf("[DEBUG] %s: %v", "An error occurred during the data request.\n", err)
+		req.Header.Set("Content-Type", "application/json")
+
+		start := time.Now()
+		resp, err = client.Do(req)
+		ch.observeRequest(method, pathStr, start, resp, err)
+
+		if !ch.retryPolicy.shouldRetry(req.Context(), method, attempt, resp, err) {
+			break
+		}
+
+		wait := ch.retryPolicy.backoff(attempt, resp)
+		discardResponse(resp)
+
+		ch.log().WarnContext(req.Context(), "retrying request", "method", method, "path", pathStr, "attempt", attempt, "wait", wait, "reason", retryReason(resp, err))
+
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
 		}
-		return resp, err
 	}
 
-	if os.Getenv("CREDHUB_DEBUG") == "true" {
-		dumpResponse(resp)
+	if err != nil {
+		return resp, err
 	}
 
 	if checkServerErr {
@@ -85,36 +94,28 @@ func (ch *CredHub) checkForServerError(resp *http.Response) error {
 			return errors.New("The response body could not be read: " + err.Error())
 		}
 
-		var respErr error
+		var method, path string
+		if resp.Request != nil {
+			method = resp.Request.Method
+			path = resp.Request.URL.Path
+		}
 
-		switch resp.StatusCode {
-		case http.StatusNotFound:
-			respErr = &NotFoundError{}
-		default:
-			respErr = &Error{}
+		// bodies which are not JSON, eg. the HTML error pages of proxies, still return the error of the status code
+		if resp.StatusCode == http.StatusNotFound {
+			respErr := &NotFoundError{StatusCode: resp.StatusCode, Method: method, Path: path, Body: body}
+			if err := json.Unmarshal(body, respErr); err != nil {
+				respErr.Description = "The response body could not be decoded: " + err.Error()
+			}
+			return respErr
 		}
 
-		if err := json.Unmarshal(body, &respErr); err != nil {
-			return errors.New("The response body could not be decoded: " + err.Error())
+		respErr := &Error{StatusCode: resp.StatusCode, Method: method, Path: path, Body: body}
+		if err := json.Unmarshal(body, respErr); err != nil {
+			respErr.Name = http.StatusText(resp.StatusCode)
+			respErr.Description = "The response body could not be decoded: " + err.Error()
 		}
 		return respErr
 	}
 
 	return nil
 }
-
-func dumpRequest(req *http.Request) {
-	dump, err := httputil.DumpRequestOut(req, true)
-	if err != nil {
-		fmt.Println("[DEBUG] An error occurred during request dump.", err.Error())
-	}
-	fmt.Println("[DEBUG]", string(dump))
-}
-
-func dumpResponse(resp *http.Response) {
-	dump, err := httputil.DumpResponse(resp, true)
-	if err != nil {
-		fmt.Println("[DEBUG] An error occurred during response dump.", err.Error())
-	}
-	fmt.Println("[DEBUG]", string(dump))
-}
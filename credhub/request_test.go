@@ -2,9 +2,13 @@ package credhub_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
 
 	. "code.cloudfoundry.org/credhub-cli/credhub"
 
@@ -57,6 +61,53 @@ var _ = Describe("Request()", func() {
 		Expect(err).To(HaveOccurred())
 	})
 
+	Context("WithContext()", func() {
+		type ctxKey struct{}
+
+		It("sends requests bound to the given context", func() {
+			mockAuth.Response = &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(""))}
+			ctx := context.WithValue(context.Background(), ctxKey{}, "some-value")
+
+			_, err := ch.WithContext(ctx).Request("GET", "/api/v1/some-endpoint", nil, nil, true)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mockAuth.Request.Context().Value(ctxKey{})).To(Equal("some-value"))
+		})
+
+		It("does not change the context of the original client", func() {
+			mockAuth.Response = &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(""))}
+			ctx := context.WithValue(context.Background(), ctxKey{}, "some-value")
+
+			Expect(ch.WithContext(ctx).Context()).To(Equal(ctx))
+
+			_, err := ch.Request("GET", "/api/v1/some-endpoint", nil, nil, true)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ch.Context()).To(Equal(context.Background()))
+			Expect(mockAuth.Request.Context().Value(ctxKey{})).To(BeNil())
+		})
+
+		It("aborts requests when the context is cancelled", func() {
+			requested := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requested = true
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			ch, err := New(server.URL)
+			Expect(err).ToNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err = ch.WithContext(ctx).GetLatestVersion("/some-credential")
+
+			Expect(err).To(MatchError(context.Canceled))
+			Expect(requested).To(BeFalse())
+		})
+	})
+
 	Context("when response body is an error ", func() {
 		Context("when checkServerError is true", func() {
 			var err error
@@ -74,6 +125,157 @@ var _ = Describe("Request()", func() {
 			})
 		})
 
+		Context("when checkServerError is true and the server responds with a well-known status code", func() {
+			var server *httptest.Server
+
+			BeforeEach(func() {
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					status, _ := strconv.Atoi(r.URL.Query().Get("status"))
+					w.WriteHeader(status)
+					w.Write([]byte(`{"error": "some-error", "error_description": "some-description"}`))
+				}))
+			})
+
+			AfterEach(func() {
+				server.Close()
+			})
+
+			DescribeTable("returns an error carrying the status code and request",
+				func(status int, sentinel error, assertType func(error)) {
+					ch, _ := New(server.URL)
+					query := url.Values{"status": {strconv.Itoa(status)}}
+
+					_, err := ch.Request("DELETE", "/api/v1/data", query, nil, true)
+
+					assertType(err)
+					Expect(errors.Is(err, sentinel)).To(BeTrue())
+
+					var httpErr *HTTPError
+					Expect(errors.As(err, &httpErr)).To(BeTrue())
+					Expect(httpErr.StatusCode).To(Equal(status))
+					Expect(httpErr.Method).To(Equal("DELETE"))
+					Expect(httpErr.Path).To(Equal("/api/v1/data"))
+					Expect(httpErr.Body).To(MatchJSON(`{"error": "some-error", "error_description": "some-description"}`))
+					Expect(httpErr.Err.Name).To(Equal("some-error"))
+				},
+				Entry("400", http.StatusBadRequest, ErrBadRequest, func(err error) {
+					Expect(err).To(BeAssignableToTypeOf(&Error{}))
+					Expect(err).To(MatchError("some-error: some-description"))
+					Expect(err.(*Error).StatusCode).To(Equal(http.StatusBadRequest))
+
+					var typedErr *BadRequestError
+					Expect(errors.As(err, &typedErr)).To(BeTrue())
+					Expect(typedErr.StatusCode).To(Equal(http.StatusBadRequest))
+				}),
+				Entry("401", http.StatusUnauthorized, ErrUnauthorized, func(err error) {
+					Expect(err).To(BeAssignableToTypeOf(&Error{}))
+
+					var typedErr *UnauthorizedError
+					Expect(errors.As(err, &typedErr)).To(BeTrue())
+				}),
+				Entry("403", http.StatusForbidden, ErrForbidden, func(err error) {
+					Expect(err).To(BeAssignableToTypeOf(&Error{}))
+					Expect(err.(*Error).Method).To(Equal("DELETE"))
+					Expect(err.(*Error).Path).To(Equal("/api/v1/data"))
+
+					var typedErr *ForbiddenError
+					Expect(errors.As(err, &typedErr)).To(BeTrue())
+					var serverErr *ServerError
+					Expect(errors.As(err, &serverErr)).To(BeFalse())
+				}),
+				Entry("404", http.StatusNotFound, ErrNotFound, func(err error) {
+					Expect(err).To(BeAssignableToTypeOf(&NotFoundError{}))
+					Expect(err).To(MatchError("some-error"))
+					Expect(err.(*NotFoundError).StatusCode).To(Equal(http.StatusNotFound))
+				}),
+				Entry("409", http.StatusConflict, ErrConflict, func(err error) {
+					Expect(err).To(BeAssignableToTypeOf(&Error{}))
+
+					var typedErr *ConflictError
+					Expect(errors.As(err, &typedErr)).To(BeTrue())
+				}),
+				Entry("500", http.StatusInternalServerError, ErrServer, func(err error) {
+					Expect(err).To(BeAssignableToTypeOf(&Error{}))
+
+					var typedErr *ServerError
+					Expect(errors.As(err, &typedErr)).To(BeTrue())
+				}),
+				Entry("503", http.StatusServiceUnavailable, ErrServer, func(err error) {
+					Expect(err).To(BeAssignableToTypeOf(&Error{}))
+
+					var typedErr *ServerError
+					Expect(errors.As(err, &typedErr)).To(BeTrue())
+					Expect(typedErr.StatusCode).To(Equal(http.StatusServiceUnavailable))
+				}),
+			)
+
+			It("returns an Error carrying the status code for other status codes", func() {
+				ch, _ := New(server.URL)
+
+				_, err := ch.Request("GET", "/api/v1/data", url.Values{"status": {"422"}}, nil, true)
+
+				Expect(err).To(BeAssignableToTypeOf(&Error{}))
+				Expect(err.(*Error).StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(errors.Is(err, ErrBadRequest)).To(BeFalse())
+			})
+		})
+
+		Context("when checkServerError is true and the response body is not JSON", func() {
+			var server *httptest.Server
+
+			BeforeEach(func() {
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					status, _ := strconv.Atoi(r.URL.Query().Get("status"))
+					w.Header().Set("Content-Type", "text/html")
+					w.WriteHeader(status)
+					w.Write([]byte("<html>" + http.StatusText(status) + "</html>"))
+				}))
+			})
+
+			AfterEach(func() {
+				server.Close()
+			})
+
+			It("returns an error of the status code saying the body could not be decoded", func() {
+				ch, _ := New(server.URL)
+
+				_, err := ch.Request("GET", "/html", url.Values{"status": {"503"}}, nil, true)
+
+				Expect(err).To(BeAssignableToTypeOf(&Error{}))
+				Expect(err).To(MatchError(HavePrefix("Service Unavailable: The response body could not be decoded: ")))
+				Expect(errors.Is(err, ErrServer)).To(BeTrue())
+
+				var serverErr *ServerError
+				Expect(errors.As(err, &serverErr)).To(BeTrue())
+				Expect(serverErr.StatusCode).To(Equal(http.StatusServiceUnavailable))
+				Expect(serverErr.Method).To(Equal("GET"))
+				Expect(serverErr.Path).To(Equal("/html"))
+				Expect(string(serverErr.Body)).To(Equal("<html>Service Unavailable</html>"))
+			})
+
+			It("returns a NotFoundError for a 404", func() {
+				ch, _ := New(server.URL)
+
+				_, err := ch.Request("GET", "/html", url.Values{"status": {"404"}}, nil, true)
+
+				Expect(err).To(BeAssignableToTypeOf(&NotFoundError{}))
+				Expect(err).To(MatchError(HavePrefix("The response body could not be decoded: ")))
+				Expect(errors.Is(err, ErrNotFound)).To(BeTrue())
+
+				var httpErr *HTTPError
+				Expect(errors.As(err, &httpErr)).To(BeTrue())
+				Expect(httpErr.StatusCode).To(Equal(http.StatusNotFound))
+				Expect(string(httpErr.Body)).To(Equal("<html>Not Found</html>"))
+			})
+		})
+
+		It("describes an HTTPError without a decoded error by its status", func() {
+			err := &HTTPError{StatusCode: http.StatusBadGateway, Method: "GET", Path: "/api/v1/data"}
+
+			Expect(err).To(MatchError("GET /api/v1/data: 502 Bad Gateway"))
+			Expect(err.Unwrap()).To(BeNil())
+		})
+
 		Context("when checkServerError is false", func() {
 			It("returns the raw response json", func() {
 				dummy := &DummyAuth{Response: &http.Response{
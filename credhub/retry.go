@@ -0,0 +1,151 @@
+package credhub
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy describes how requests that fail with a transient error are retried.
+//
+// A request is retried when the connection to the server fails, or when the server
+// responds with 429 Too Many Requests, 502 Bad Gateway, 503 Service Unavailable or
+// 504 Gateway Timeout. Only idempotent requests (GET, HEAD, OPTIONS and DELETE) are
+// retried, unless RetryPut is set.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a request, including the first one.
+	// Values lower than 2 disable retries.
+	MaxAttempts int
+
+	// InitialBackoff is the upper bound of the wait before the first retry. The bound doubles
+	// on every following retry, and the actual wait is chosen at random below it.
+	// Defaults to 500ms.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait between two attempts, including waits requested by the server
+	// through the Retry-After header. Defaults to 10s.
+	MaxBackoff time.Duration
+
+	// RetryPut allows PUT requests to be retried. Retrying a PUT to /api/v1/data may
+	// create an additional credential version if the first attempt reached the server.
+	RetryPut bool
+}
+
+// DefaultRetryPolicy retries idempotent requests up to two times
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+}
+
+func (p RetryPolicy) retryable(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodDelete:
+		return true
+	case http.MethodPut:
+		return p.RetryPut
+	default:
+		return false
+	}
+}
+
+func (p RetryPolicy) shouldRetry(ctx context.Context, method string, attempt int, resp *http.Response, err error) bool {
+	if attempt >= p.MaxAttempts || !p.retryable(method) || ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return isTransientError(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := retryAfter(resp); ok {
+			return min(wait, p.MaxBackoff)
+		}
+	}
+
+	// Doubling stops at MaxBackoff, so that the bound cannot overflow after many attempts
+	limit := p.InitialBackoff
+	for i := 1; i < attempt && limit > 0 && limit < p.MaxBackoff; i++ {
+		limit <<= 1
+	}
+	if limit <= 0 || limit > p.MaxBackoff {
+		limit = p.MaxBackoff
+	}
+
+	return rand.N(limit + 1)
+}
+
+// isTransientError reports whether err was returned while talking to the server,
+// as opposed to errors obtaining credentials or validating the server's certificate
+func isTransientError(err error) bool {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return false
+	}
+
+	var certErr *tls.CertificateVerificationError
+	return !errors.As(err, &certErr)
+}
+
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+func retryReason(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func discardResponse(resp *http.Response) {
+	if resp != nil && resp.Body != nil {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
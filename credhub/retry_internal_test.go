@@ -0,0 +1,28 @@
+package credhub
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RetryPolicy backoff", func() {
+	It("stays within MaxBackoff however many attempts were made", func() {
+		policy := RetryPolicy{InitialBackoff: 500 * time.Millisecond, MaxBackoff: 10 * time.Second}
+
+		for _, attempt := range []int{1, 5, 34, 35, 64, 100, 1000} {
+			backoff := policy.backoff(attempt, nil)
+			Expect(backoff).To(BeNumerically(">=", 0), "attempt %d", attempt)
+			Expect(backoff).To(BeNumerically("<=", 10*time.Second), "attempt %d", attempt)
+		}
+	})
+
+	It("doubles the bound on every attempt", func() {
+		policy := RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: time.Hour}
+
+		for i := 0; i < 100; i++ {
+			Expect(policy.backoff(3, nil)).To(BeNumerically("<=", 4*time.Millisecond))
+		}
+	})
+})
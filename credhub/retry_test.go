@@ -0,0 +1,146 @@
+package credhub_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "code.cloudfoundry.org/credhub-cli/credhub"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retry", func() {
+	var (
+		server    *httptest.Server
+		attempts  atomic.Int32
+		responses []int
+		policy    RetryPolicy
+	)
+
+	BeforeEach(func() {
+		attempts.Store(0)
+		responses = nil
+		policy = RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     5 * time.Millisecond,
+		}
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempt := int(attempts.Add(1))
+			status := http.StatusOK
+			if attempt <= len(responses) {
+				status = responses[attempt-1]
+			}
+			if status == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "120")
+			}
+			w.WriteHeader(status)
+			w.Write([]byte(`{"error":"some-error"}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("retries GET requests which fail with a transient error", func() {
+		responses = []int{http.StatusBadGateway, http.StatusServiceUnavailable}
+		ch, _ := New(server.URL, Retry(policy))
+
+		resp, err := ch.Request(http.MethodGet, "/api/v1/data", nil, nil, true)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(attempts.Load()).To(BeEquivalentTo(3))
+	})
+
+	It("returns the last response once the attempts are exhausted", func() {
+		responses = []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+		ch, _ := New(server.URL, Retry(policy))
+
+		resp, err := ch.Request(http.MethodGet, "/api/v1/data", nil, nil, false)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusGatewayTimeout))
+		Expect(attempts.Load()).To(BeEquivalentTo(3))
+	})
+
+	It("caps the wait requested by the Retry-After header at MaxBackoff", func() {
+		responses = []int{http.StatusTooManyRequests}
+		ch, _ := New(server.URL, Retry(policy))
+
+		start := time.Now()
+		_, err := ch.Request(http.MethodDelete, "/api/v1/data", nil, nil, true)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attempts.Load()).To(BeEquivalentTo(2))
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+	})
+
+	It("does not retry errors which are not transient", func() {
+		responses = []int{http.StatusInternalServerError}
+		ch, _ := New(server.URL, Retry(policy))
+
+		_, err := ch.Request(http.MethodGet, "/api/v1/data", nil, nil, true)
+
+		Expect(err).To(MatchError("some-error"))
+		Expect(attempts.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("does not retry POST requests", func() {
+		responses = []int{http.StatusServiceUnavailable}
+		ch, _ := New(server.URL, Retry(policy))
+
+		_, err := ch.Request(http.MethodPost, "/api/v1/data", nil, map[string]string{}, true)
+
+		Expect(err).To(HaveOccurred())
+		Expect(attempts.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("retries PUT requests only when RetryPut is set", func() {
+		responses = []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable}
+		ch, _ := New(server.URL, Retry(policy))
+
+		_, err := ch.Request(http.MethodPut, "/api/v1/data", nil, map[string]string{"name": "some-name"}, true)
+		Expect(err).To(HaveOccurred())
+		Expect(attempts.Load()).To(BeEquivalentTo(1))
+
+		policy.RetryPut = true
+		ch, _ = New(server.URL, Retry(policy))
+
+		resp, err := ch.Request(http.MethodPut, "/api/v1/data", nil, map[string]string{"name": "some-name"}, true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(attempts.Load()).To(BeEquivalentTo(3))
+	})
+
+	It("does not retry without a retry policy", func() {
+		responses = []int{http.StatusServiceUnavailable}
+		ch, _ := New(server.URL)
+
+		_, err := ch.Request(http.MethodGet, "/api/v1/data", nil, nil, true)
+
+		Expect(err).To(HaveOccurred())
+		Expect(attempts.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("stops waiting when the context is cancelled", func() {
+		responses = []int{http.StatusServiceUnavailable}
+		policy.InitialBackoff = time.Hour
+		policy.MaxBackoff = time.Hour
+		ch, _ := New(server.URL, Retry(policy))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := ch.WithContext(ctx).Request(http.MethodGet, "/api/v1/data", nil, nil, true)
+
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(attempts.Load()).To(BeEquivalentTo(1))
+	})
+})
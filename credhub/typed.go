@@ -0,0 +1,79 @@
+package credhub
+
+import (
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+)
+
+// GetLatest returns the current version of the named credential as the typed credential T,
+// eg. GetLatest[credentials.Certificate](ch, name)
+//
+// A *credentials.TypeMismatchError is returned when the credential has another type.
+func GetLatest[T credentials.Typed](ch Reader, name string) (T, error) {
+	cred, err := ch.GetLatestVersion(name)
+	if err != nil {
+		var typed T
+		return typed, err
+	}
+	return credentials.As[T](cred)
+}
+
+// GetVersions returns all versions of the named credential as the typed credential T, newest first
+func GetVersions[T credentials.Typed](ch Reader, name string) ([]T, error) {
+	creds, err := ch.GetAllVersions(name)
+	if err != nil {
+		return nil, err
+	}
+	return convertAll[T](creds)
+}
+
+// GetNVersions returns the numberOfVersions newest versions of the named credential as the typed credential T
+func GetNVersions[T credentials.Typed](ch Reader, name string, numberOfVersions int) ([]T, error) {
+	creds, err := ch.GetNVersions(name, numberOfVersions)
+	if err != nil {
+		return nil, err
+	}
+	return convertAll[T](creds)
+}
+
+// GetByID returns the credential version with the given ID as the typed credential T
+func GetByID[T credentials.Typed](ch Reader, id string) (T, error) {
+	cred, err := ch.GetById(id)
+	if err != nil {
+		var typed T
+		return typed, err
+	}
+	return credentials.As[T](cred)
+}
+
+// Set sets a new version of the named credential, of the type of T, and returns it as T. The value must
+// encode to the value of that type, eg. a values.Certificate for credentials.Certificate.
+func Set[T credentials.Typed](ch Writer, name string, value interface{}, options ...SetOption) (T, error) {
+	cred, err := ch.SetCredential(name, credentials.TypeOf[T](), value, options...)
+	if err != nil {
+		var typed T
+		return typed, err
+	}
+	return credentials.As[T](cred)
+}
+
+// Generate generates the named credential, of the type of T, and returns it as T. The parameters must
+// encode to the parameters of that type, eg. a generate.Certificate for credentials.Certificate.
+func Generate[T credentials.Typed](ch Generator, name string, parameters interface{}, overwrite Mode, options ...GenerateOption) (T, error) {
+	cred, err := ch.GenerateCredential(name, credentials.TypeOf[T](), parameters, overwrite, options...)
+	if err != nil {
+		var typed T
+		return typed, err
+	}
+	return credentials.As[T](cred)
+}
+
+func convertAll[T credentials.Typed](creds []credentials.Credential) ([]T, error) {
+	typed := make([]T, len(creds))
+	for i, cred := range creds {
+		var err error
+		if typed[i], err = credentials.As[T](cred); err != nil {
+			return nil, err
+		}
+	}
+	return typed, nil
+}
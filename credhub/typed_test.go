@@ -0,0 +1,76 @@
+package credhub_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/generate"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/values"
+	"code.cloudfoundry.org/credhub-cli/credhub/credhubtest"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Typed credentials", func() {
+	var ch *credhub.CredHub
+
+	BeforeEach(func() {
+		server := credhubtest.NewServer()
+		DeferCleanup(server.Close)
+
+		var err error
+		ch, err = server.CredHub()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("sets and gets typed credentials", func() {
+		first, err := credhub.Set[credentials.User](ch, "/some-user", values.User{Username: "first", Password: "some-password"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(first.Value.PasswordHash).To(HavePrefix("$6$"))
+		_, err = credhub.Set[credentials.User](ch, "/some-user", values.User{Username: "second", Password: "some-password"})
+		Expect(err).NotTo(HaveOccurred())
+
+		latest, err := credhub.GetLatest[credentials.User](ch, "/some-user")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(latest.Value.Username).To(Equal("second"))
+
+		versions, err := credhub.GetVersions[credentials.User](ch, "/some-user")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(versions).To(HaveLen(2))
+		Expect(versions[1].Value.Username).To(Equal("first"))
+
+		versions, err = credhub.GetNVersions[credentials.User](ch, "/some-user", 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(versions).To(HaveLen(1))
+
+		byID, err := credhub.GetByID[credentials.User](ch, first.Id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(byID).To(Equal(first))
+	})
+
+	It("generates typed credentials", func() {
+		cert, err := credhub.Generate[credentials.Certificate](ch, "/some-ca", generate.Certificate{CommonName: "some-ca", IsCA: true}, credhub.Overwrite)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert.Value.Certificate).To(HavePrefix("-----BEGIN CERTIFICATE-----"))
+	})
+
+	It("returns a type mismatch error for a credential of another type", func() {
+		_, err := ch.SetPassword("/some-password", "some-password")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = credhub.GetLatest[credentials.Certificate](ch, "/some-password")
+
+		var mismatch *credentials.TypeMismatchError
+		Expect(errors.As(err, &mismatch)).To(BeTrue())
+		Expect(mismatch.Type).To(Equal("password"))
+		Expect(mismatch.Expected).To(Equal("certificate"))
+	})
+
+	It("returns the errors of the server", func() {
+		_, err := credhub.GetLatest[credentials.Value](ch, "/missing")
+
+		Expect(errors.Is(err, credhub.ErrNotFound)).To(BeTrue())
+	})
+})
@@ -57,6 +57,41 @@ func NewClientAuthorizationParametersError() error {
 	return errors.New("Both client name and client secret must be provided to authenticate. Please update and retry your request.")
 }
 
+func NewClientCertificateParametersError() error {
+	return errors.New("Both client certificate and client key must be provided to authenticate with a client certificate. Please update and retry your request.")
+}
+
+func NewClientCertificateLoadError(err error) error {
+	return errors.New("The client certificate could not be loaded: " + err.Error())
+}
+
+func NewOIDCSSOError() error {
+	return errors.New("SSO passcodes are not supported by OpenID Connect providers. Please log in with a username and password or with client credentials.")
+}
+
+func NewOIDCDeviceError() error {
+	return errors.New("Device login is only supported by UAA. Please log in with a browser, a username and password or client credentials.")
+}
+
+func NewExecArgsWithoutCommandError() error {
+	return errors.New("The exec command must be provided with its arguments. Please update and retry your request.")
+}
+
+func NewBrowserLoginNotSupportedError() error {
+	return errors.New("The auth server does not support logging in with a browser. Please log in with a username and password or with client credentials.")
+}
+
+func NewBrowserLoginTimeoutError() error {
+	return errors.New("Timed out waiting for the login to complete in the browser. Please retry your request.")
+}
+
+func NewBrowserLoginDeniedError(name, description string) error {
+	if description == "" {
+		return fmt.Errorf("The browser login failed: %s", name)
+	}
+	return fmt.Errorf("The browser login failed: %s %s", name, description)
+}
+
 func NewRefreshError() error {
 	return errors.New("You are not currently authenticated. Please log in to continue.")
 }
@@ -77,6 +112,57 @@ func NewNoApiUrlSetError() error {
 	return errors.New("An API target is not set. Please target the location of your server with `credhub api --server api.example.com` to continue.")
 }
 
+func NewTargetNotFoundError(name string) error {
+	return fmt.Errorf("The target '%s' does not exist. Run `credhub target list` to see the saved targets.", name)
+}
+
+func NewTargetExistsError(name string) error {
+	return fmt.Errorf("The target '%s' already exists. Run `credhub target delete %s` to replace it, or target its server with `credhub --target %s api`.", name, name, name)
+}
+
+func NewTokenStoreKeyMissingError() error {
+	return errors.New("The saved tokens are encrypted with a passphrase or key file which is not available. Set CREDHUB_TOKEN_PASSPHRASE, or run `credhub token-store encrypted-file --key-file <file>` to use a key file.")
+}
+
+func NewTokenStoreDecryptError() error {
+	return errors.New("The saved tokens could not be decrypted. Please check the passphrase in CREDHUB_TOKEN_PASSPHRASE or the token key file.")
+}
+
+func NewUnknownTokenStoreError(name string) error {
+	return fmt.Errorf("The token store '%s' is not supported. Valid token stores are 'plaintext' and 'encrypted-file'.", name)
+}
+
+func NewInvalidExpiryDurationError(duration string) error {
+	return fmt.Errorf("The duration '%s' is not valid. Please use a number of days such as 30d, or a duration such as 12h.", duration)
+}
+
+func NewCertificatesExpiringError(count int, duration string) error {
+	if count == 1 {
+		return fmt.Errorf("1 certificate expires within %s.", duration)
+	}
+	return fmt.Errorf("%d certificates expire within %s.", count, duration)
+}
+
+func NewUnknownCertificateError(name string) error {
+	return fmt.Errorf("The certificate '%s' could not be found. Please validate the name and retry your request.", name)
+}
+
+func NewNotACertificateAuthorityError(name string) error {
+	return fmt.Errorf("The certificate '%s' is not a certificate authority. Only certificate authorities can be rotated.", name)
+}
+
+func NewRotationInProgressError(name string) error {
+	return fmt.Errorf("A rotation of '%s' is already in progress. Please continue it with --step swap or --step clean.", name)
+}
+
+func NewRotationStepOutOfOrderError(name, step, previousStep string) error {
+	return fmt.Errorf("The '%s' step of the rotation of '%s' cannot be run yet. Please run the '%s' step first.", step, name, previousStep)
+}
+
+func NewRotationRegenerateError(name string, err error) error {
+	return fmt.Errorf("The new version of '%s' signs certificates, but the certificates it signs could not be regenerated: %s. Please run the 'swap' step again to regenerate them.", name, err.Error())
+}
+
 func NewInvalidImportYamlError() error {
 	return errors.New("The referenced file does not contain valid yaml structure. Please update and retry your request.")
 }
@@ -101,6 +187,10 @@ func NewOutputJSONAndQuietError() error {
 	return errors.New("The --output-json flag and --quiet flag are incompatible.")
 }
 
+func NewDryRunAndReportError() error {
+	return errors.New("The --dry-run flag and --report flag are incompatible.")
+}
+
 func NewUserNameOnlyValidForUserType() error {
 	return errors.New("Username parameter is not valid for this credential type.")
 }
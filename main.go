@@ -4,6 +4,7 @@ import (
 	"os"
 	"runtime"
 	"runtime/debug"
+	"strconv"
 	"time"
 
 	"github.com/cloudfoundry/bosh-utils/errors"
@@ -11,13 +12,12 @@ import (
 	"code.cloudfoundry.org/credhub-cli/commands"
 	"code.cloudfoundry.org/credhub-cli/config"
 	"code.cloudfoundry.org/credhub-cli/credhub"
-	"code.cloudfoundry.org/credhub-cli/credhub/auth"
 	"code.cloudfoundry.org/credhub-cli/util"
 	"github.com/jessevdk/go-flags"
 )
 
 type NeedsClient interface {
-	SetClient(*credhub.CredHub)
+	SetClient(credhub.Client)
 }
 type NeedsConfig interface {
 	SetConfig(config.Config)
@@ -37,39 +37,28 @@ func main() {
 			_ = os.Setenv("CREDHUB_HTTP_TIMEOUT", timeout.String())
 		}
 
+		if retries := parser.FindOptionByLongName("retries").Value().(*int); retries != nil {
+			_ = os.Setenv("CREDHUB_RETRIES", strconv.Itoa(*retries))
+		}
+
+		if parser.FindOptionByLongName("retry-put").Value().(bool) {
+			_ = os.Setenv("CREDHUB_RETRY_PUT", "true")
+		}
+
+		if logFile := parser.FindOptionByLongName("log-file").Value().(string); logFile != "" {
+			_ = os.Setenv("CREDHUB_LOG_FILE", logFile)
+		}
+
+		if target := parser.FindOptionByLongName("target").Value().(string); target != "" {
+			_ = os.Setenv("CREDHUB_TARGET", target)
+		}
+
 		if cmd, ok := command.(NeedsConfig); ok {
 			cmd.SetConfig(config.ReadConfig())
 		}
 
 		if cmd, ok := command.(NeedsClient); ok {
-			cfg := config.ReadConfig()
-			if err := config.ValidateConfig(cfg); err != nil {
-				return err
-			}
-			clientId := cfg.ClientID
-			clientSecret := cfg.ClientSecret
-			useClientCredentials := true
-			if clientId == "" {
-				clientId = config.AuthClient
-				clientSecret = config.AuthPassword
-				useClientCredentials = false
-			}
-			client, err := credhub.New(cfg.ApiURL,
-				credhub.AuthURL(cfg.AuthURL),
-				credhub.CaCerts(cfg.CaCerts...),
-				credhub.SkipTLSValidation(cfg.InsecureSkipVerify),
-				credhub.Auth(auth.Uaa(
-					clientId,
-					clientSecret,
-					"",
-					"",
-					cfg.AccessToken,
-					cfg.RefreshToken,
-					useClientCredentials,
-				)),
-				credhub.ServerVersion(cfg.ServerVersion),
-				credhub.SetHttpTimeout(cfg.HttpTimeout),
-			)
+			client, err := config.Client()
 			if err != nil {
 				return err
 			}
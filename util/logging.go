@@ -1,8 +1,13 @@
 package util
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"strings"
+	"sync"
 )
 
 const (
@@ -20,3 +25,83 @@ func Warning(msg string) {
 func Error(msg string) {
 	fmt.Fprintf(os.Stderr, ErrorColor+"\n", msg)
 }
+
+var (
+	debugLogger     *slog.Logger
+	debugLoggerOnce sync.Once
+)
+
+// DebugLogger returns the logger for the CLI debug output, or nil when debug output is disabled.
+//
+// Debug output is appended to the file named by CREDHUB_LOG_FILE if set, and written to
+// stderr otherwise when CREDHUB_DEBUG is "true". It is never written to stdout, so that
+// it does not corrupt the output of commands.
+func DebugLogger() *slog.Logger {
+	debugLoggerOnce.Do(func() {
+		if logFile := os.Getenv("CREDHUB_LOG_FILE"); logFile != "" {
+			f, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+			if err == nil {
+				debugLogger = NewDebugLogger(f)
+				return
+			}
+			Warning(fmt.Sprintf("Unable to open log file, debug output will be written to stderr: %s", err))
+		} else if os.Getenv("CREDHUB_DEBUG") != "true" {
+			return
+		}
+
+		debugLogger = NewDebugLogger(os.Stderr)
+	})
+
+	return debugLogger
+}
+
+// NewDebugLogger returns a logger writing every record to w as "[LEVEL] message key=value ...".
+// Multi-line values, such as request dumps, are written verbatim on the following lines.
+func NewDebugLogger(w io.Writer) *slog.Logger {
+	return slog.New(&debugHandler{mu: &sync.Mutex{}, w: w})
+}
+
+type debugHandler struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	attrs []slog.Attr
+}
+
+func (h *debugHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *debugHandler) Handle(_ context.Context, r slog.Record) error {
+	var line, details strings.Builder
+
+	fmt.Fprintf(&line, "[%s] %s", r.Level, r.Message)
+
+	attrs := append([]slog.Attr{}, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	for _, a := range attrs {
+		value := a.Value.Resolve().String()
+		if strings.Contains(value, "\n") {
+			details.WriteString(strings.TrimRight(value, "\r\n") + "\n")
+		} else {
+			fmt.Fprintf(&line, " %s=%s", a.Key, value)
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, err := io.WriteString(h.w, line.String()+"\n"+details.String())
+	return err
+}
+
+func (h *debugHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &debugHandler{mu: h.mu, w: h.w, attrs: append(append([]slog.Attr{}, h.attrs...), attrs...)}
+}
+
+func (h *debugHandler) WithGroup(string) slog.Handler {
+	return h
+}
@@ -0,0 +1,29 @@
+package util_test
+
+import (
+	"bytes"
+
+	"code.cloudfoundry.org/credhub-cli/util"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Logging", func() {
+	Describe("#NewDebugLogger", func() {
+		It("writes the level, message and attributes on one line", func() {
+			out := &bytes.Buffer{}
+
+			util.NewDebugLogger(out).With("method", "GET").Debug("sending request", "url", "https://example.com")
+
+			Expect(out.String()).To(Equal("[DEBUG] sending request method=GET url=https://example.com\n"))
+		})
+
+		It("writes multi-line attributes verbatim after the message", func() {
+			out := &bytes.Buffer{}
+
+			util.NewDebugLogger(out).Warn("received response", "status", 200, "dump", "HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n")
+
+			Expect(out.String()).To(Equal("[WARN] received response status=200\nHTTP/1.1 200 OK\r\nContent-Length: 0\n"))
+		})
+	})
+})